@@ -1,9 +1,14 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"io"
 	"os"
+	"os/signal"
 	"runtime/debug"
+	"syscall"
 
 	"github.com/anselstetter/git-build-number/internal/buildnumber"
 	"github.com/anselstetter/git-build-number/internal/cmd"
@@ -18,27 +23,43 @@ func main() {
 
 func run(args []string, stdout io.Writer, stderr io.Writer, buildInfoFunc version.BuildInfoFunc) int {
 	logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(stderr))
-	repo, err := repository.NewGitRepository(".")
-	if err != nil {
-		return fail(logger, err, 1)
-	}
+	ctx, cancel := newContext()
+	defer cancel()
+	repo := cmd.NewLazyRepository(open)
+	defer repo.Close()
 	version := version.New(buildInfoFunc, "Dev")
 	buildNumber := buildnumber.New(repo)
-	root := cmd.NewRootCommand()
+	root := cmd.NewRootCommand(repo, logger)
 
 	root.AddCommand(
 		cmd.NewVersionCommand(version, logger),
 		cmd.NewGetCommand(buildNumber, logger),
 		cmd.NewSetCommand(buildNumber, logger),
 		cmd.NewIncCommand(buildNumber, logger),
+		cmd.NewNextCommand(buildNumber, logger),
 		cmd.NewPushCommand(buildNumber, logger),
 		cmd.NewFetchCommand(buildNumber, logger),
+		cmd.NewStatusCommand(buildNumber, logger),
+		cmd.NewReconcileCommand(buildNumber, logger),
 		cmd.NewHashCommand(buildNumber, logger),
+		cmd.NewStampCommand(buildNumber, logger),
+		cmd.NewWorkspaceStatusCommand(buildNumber, logger),
+		cmd.NewExecCommand(buildNumber, logger, os.Stdin),
 		cmd.NewNamespaceCommand(
 			cmd.NewNamespaceListCommand(buildNumber, logger),
-			cmd.NewNamespaceDeleteCommand(buildNumber, logger),
+			cmd.NewNamespaceCreateCommand(buildNumber, logger),
+			cmd.NewNamespaceDeleteCommand(buildNumber, logger, os.Stdin),
 			cmd.NewNamespaceMirrorCommand(buildNumber, logger, os.Stdin),
 			cmd.NewNamespaceClearCommand(buildNumber, logger, os.Stdin),
+			cmd.NewNamespacePruneCommand(buildNumber, logger, os.Stdin),
+			cmd.NewNamespaceRenameCommand(buildNumber, logger),
+			cmd.NewNamespaceCopyCommand(buildNumber, logger),
+			cmd.NewNamespaceMetadataCommand(buildNumber, logger),
+			cmd.NewNamespaceAliasCommand(
+				cmd.NewNamespaceAliasSetCommand(buildNumber, logger),
+				cmd.NewNamespaceAliasRemoveCommand(buildNumber, logger),
+				cmd.NewNamespaceAliasListCommand(buildNumber, logger),
+			),
 		),
 		cmd.NewGenerateDocsCommand(),
 	)
@@ -46,12 +67,124 @@ func run(args []string, stdout io.Writer, stderr io.Writer, buildInfoFunc versio
 	root.SetOut(stdout)
 	root.SetErr(stderr)
 
-	if err := root.Execute(); err != nil {
+	if executed, err := root.ExecuteContextC(ctx); err != nil {
+		if executed.Context().Err() != nil {
+			return fail(logger, context.Cause(executed.Context()), 1)
+		}
+		var exitErr cmd.ExitCodeError
+		if errors.As(err, &exitErr) {
+			return fail(logger, err, exitErr.Code)
+		}
 		return fail(logger, err, 1)
 	}
 	return 0
 }
 
+func newContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case sig := <-signals:
+			cancel(fmt.Errorf("%w: %s", cmd.ErrInterrupted, sig))
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, func() {
+		signal.Stop(signals)
+		cancel(nil)
+	}
+}
+
+func open(options cmd.Options) (repository.Repository, func(), error) {
+	options.TLS.NoProxy = noProxy()
+	auth := repository.AuthFromEnv(os.Getenv)
+	if options.SSHKey != "" {
+		auth.SSHKey = options.SSHKey
+	}
+	auth.KnownHosts = options.KnownHosts
+
+	switch options.Backend {
+	case "go-git":
+		repo, err := openGoGit(options.Directory, options.GitDir, options.RemoteURL)
+		if err != nil {
+			return nil, nil, err
+		}
+		repo.SetAuth(auth)
+		config, err := repository.TLSFromConfig(repo)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := repo.SetTLS(config.Merge(options.TLS)); err != nil {
+			return nil, nil, err
+		}
+		return repo, func() {}, nil
+	case "git":
+		repo, closeRepo, err := openGit(options.Directory, options.GitDir, options.RemoteURL)
+		if err != nil {
+			return nil, nil, err
+		}
+		repo.SetAuth(auth)
+		if err := repo.SetTLS(options.TLS); err != nil {
+			closeRepo()
+			return nil, nil, err
+		}
+		return repo, func() {
+			_ = repo.Close()
+			closeRepo()
+		}, nil
+	default:
+		return nil, nil, fmt.Errorf("%w: %s", cmd.ErrUnknownBackend, options.Backend)
+	}
+}
+
+func openGoGit(directory string, gitDir string, remoteURL string) (*repository.GitRepository, error) {
+	if remoteURL != "" {
+		repo, _, err := repository.NewGitInMemoryRepository(false)
+		if err != nil {
+			return nil, err
+		}
+		repo.SetUnknownRevisions(true)
+		return repo, repo.AddRemote(cmd.RemoteURLName, remoteURL)
+	}
+	if gitDir != "" {
+		return repository.NewGitDirRepository(gitDir)
+	}
+	return repository.NewGitRepository(directory)
+}
+
+func openGit(directory string, gitDir string, remoteURL string) (*repository.CLIRepository, func(), error) {
+	if remoteURL != "" {
+		repo, path, err := repository.NewCLITempBareRepository(false)
+		if err != nil {
+			return nil, nil, err
+		}
+		closeRepo := func() {
+			_ = os.RemoveAll(*path)
+		}
+		repo.SetUnknownRevisions(true)
+		if err := repo.AddRemote(cmd.RemoteURLName, remoteURL); err != nil {
+			closeRepo()
+			return nil, nil, err
+		}
+		return repo, closeRepo, nil
+	}
+	if gitDir != "" {
+		repo, err := repository.NewCLIGitDirRepository(gitDir)
+		return repo, func() {}, err
+	}
+	repo, err := repository.NewCLIRepository(directory)
+	return repo, func() {}, err
+}
+
+func noProxy() string {
+	if value := os.Getenv("NO_PROXY"); value != "" {
+		return value
+	}
+	return os.Getenv("no_proxy")
+}
+
 func fail(logger logger.Logger, err error, exitCode int) int {
 	logger.Stderrf("%s\n", err.Error())
 	return exitCode
@@ -2,9 +2,16 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
 	"runtime/debug"
+	"syscall"
 	"testing"
 
+	"github.com/anselstetter/git-build-number/internal/cmd"
+	"github.com/anselstetter/git-build-number/internal/repository"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -33,6 +40,195 @@ func TestRun(t *testing.T) {
 		assert.Equal(t, "", stdout.String())
 		assert.Contains(t, stderr.String(), "unknown command")
 	})
+	t.Run("exit code of exec", func(t *testing.T) {
+		t.Parallel()
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		code := run([]string{"exec", "--push=false", "--", "sh", "-c", "exit 3"}, stdout, stderr, buildInfo)
+
+		assert.Equal(t, 3, code)
+		assert.Equal(t, "", stdout.String())
+		assert.Contains(t, stderr.String(), "exited with code 3")
+	})
+	t.Run("-C and --git-dir", func(t *testing.T) {
+		t.Parallel()
+
+		_, path, err := repository.NewGitTempBareRepository(true)
+		assert.NoError(t, err)
+		t.Cleanup(func() {
+			_ = os.RemoveAll(*path)
+		})
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		code := run([]string{"-C", *path, "set", "5"}, stdout, stderr, buildInfo)
+		assert.Equal(t, 0, code)
+		assert.Equal(t, "", stderr.String())
+
+		stdout.Reset()
+		code = run([]string{"get", "--git-dir", filepath.Base(*path), "-C", filepath.Dir(*path)}, stdout, stderr, buildInfo)
+		assert.Equal(t, 0, code)
+		assert.Equal(t, "", stderr.String())
+		assert.Equal(t, "5\n", stdout.String())
+	})
+	t.Run("--remote-url", func(t *testing.T) {
+		t.Parallel()
+
+		remote, path, err := repository.NewGitTempBareRepository(true)
+		assert.NoError(t, err)
+		t.Cleanup(func() {
+			_ = os.RemoveAll(*path)
+		})
+		head, err := remote.Head()
+		assert.NoError(t, err)
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		code := run([]string{"--remote-url", *path, "-C", t.TempDir(), "inc", "--rev", head.Hash, "--force"}, stdout, stderr, buildInfo)
+		assert.Equal(t, 0, code)
+		assert.Equal(t, "", stderr.String())
+		assert.Equal(t, "2\n", stdout.String())
+
+		stdout.Reset()
+		code = run([]string{"-C", *path, "get"}, stdout, stderr, buildInfo)
+		assert.Equal(t, 0, code)
+		assert.Equal(t, "2\n", stdout.String())
+	})
+	t.Run("--timeout", func(t *testing.T) {
+		t.Parallel()
+
+		remote, path, err := repository.NewGitTempBareRepository(true)
+		assert.NoError(t, err)
+		t.Cleanup(func() {
+			_ = os.RemoveAll(*path)
+		})
+		head, err := remote.Head()
+		assert.NoError(t, err)
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		code := run([]string{"--timeout", "1ns", "--remote-url", *path, "inc", "--rev", head.Hash}, stdout, stderr, buildInfo)
+		assert.Equal(t, 1, code)
+		assert.Equal(t, "timed out after 1ns\n", stderr.String())
+		assert.Equal(t, "", stdout.String())
+
+		refs, err := remote.Refs(repository.WithPrefix("refs/build-number/"))
+		assert.NoError(t, err)
+		assert.Empty(t, refs)
+	})
+	t.Run("--backend git", func(t *testing.T) {
+		t.Parallel()
+
+		_, path, err := repository.NewCLITempBareRepository(true)
+		assert.NoError(t, err)
+		t.Cleanup(func() {
+			_ = os.RemoveAll(*path)
+		})
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		code := run([]string{"--backend", "git", "-C", *path, "set", "5"}, stdout, stderr, buildInfo)
+		assert.Equal(t, 0, code)
+		assert.Equal(t, "", stderr.String())
+
+		stdout.Reset()
+		code = run([]string{"-C", *path, "get"}, stdout, stderr, buildInfo)
+		assert.Equal(t, 0, code)
+		assert.Equal(t, "5\n", stdout.String())
+	})
+	t.Run("unknown backend", func(t *testing.T) {
+		t.Parallel()
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		code := run([]string{"--backend", "svn", "get"}, stdout, stderr, buildInfo)
+		assert.Equal(t, 1, code)
+		assert.Equal(t, "unknown backend: svn\n", stderr.String())
+	})
+	t.Run("--client-key without --client-cert", func(t *testing.T) {
+		t.Parallel()
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		code := run([]string{"--client-key", "key.pem", "get"}, stdout, stderr, buildInfo)
+		assert.Equal(t, 1, code)
+		assert.Equal(t, cmd.ErrClientKey.Error()+"\n", stderr.String())
+	})
+	t.Run("--insecure-skip-tls-verify", func(t *testing.T) {
+		t.Parallel()
+
+		_, path, err := repository.NewGitTempBareRepository(true)
+		assert.NoError(t, err)
+		t.Cleanup(func() {
+			_ = os.RemoveAll(*path)
+		})
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		code := run([]string{"-C", *path, "--insecure-skip-tls-verify", "set", "5"}, stdout, stderr, buildInfo)
+		assert.Equal(t, 0, code)
+		assert.Equal(t, "warning: TLS certificate verification is disabled, remotes can be impersonated\n", stderr.String())
+	})
+	t.Run("--ca-bundle without certificates", func(t *testing.T) {
+		t.Parallel()
+
+		_, path, err := repository.NewGitTempBareRepository(true)
+		assert.NoError(t, err)
+		t.Cleanup(func() {
+			_ = os.RemoveAll(*path)
+		})
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		code := run([]string{"-C", *path, "--ca-bundle", filepath.Join(t.TempDir(), "ca.pem"), "get"}, stdout, stderr, buildInfo)
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stderr.String(), repository.ErrInvalidCABundle.Error())
+	})
+	t.Run("-C without repository", func(t *testing.T) {
+		t.Parallel()
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		code := run([]string{"-C", t.TempDir(), "get"}, stdout, stderr, buildInfo)
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stderr.String(), "repository does not exist")
+	})
+}
+
+func TestNewContext(t *testing.T) {
+	t.Run("signal", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("signals are not supported on windows")
+		}
+		ctx, cancel := newContext()
+		defer cancel()
+
+		process, err := os.FindProcess(os.Getpid())
+		assert.NoError(t, err)
+		assert.NoError(t, process.Signal(syscall.SIGTERM))
+
+		<-ctx.Done()
+		assert.ErrorIs(t, context.Cause(ctx), cmd.ErrInterrupted)
+		assert.EqualError(t, context.Cause(ctx), "interrupted: terminated")
+	})
+	t.Run("cancel", func(t *testing.T) {
+		ctx, cancel := newContext()
+		assert.NoError(t, ctx.Err())
+
+		cancel()
+		assert.ErrorIs(t, ctx.Err(), context.Canceled)
+	})
 }
 
 func buildInfo() (info *debug.BuildInfo, ok bool) {
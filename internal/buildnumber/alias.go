@@ -0,0 +1,63 @@
+package buildnumber
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/anselstetter/git-build-number/internal/repository"
+)
+
+var (
+	ErrNotAnAlias = errors.New("namespace is not an alias")
+)
+
+type Alias struct {
+	Name   string
+	Target string
+}
+
+func (bn *BuildNumber) Alias(alias string, target string) error {
+	aliases, err := bn.Aliases()
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(aliases, func(a Alias) bool { return a.Name == alias }) {
+		if _, err := bn.entry(bn.ref(alias)); err == nil {
+			return fmt.Errorf("%w: %s", ErrNamespaceExists, alias)
+		} else if !errors.Is(err, ErrBuildNumberNotFound) {
+			return err
+		}
+	}
+	if _, err := bn.entry(bn.ref(target)); err != nil {
+		return err
+	}
+	return bn.repository.Alias(bn.ref(alias), bn.ref(target))
+}
+
+func (bn *BuildNumber) Unalias(alias string) error {
+	aliases, err := bn.Aliases()
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(aliases, func(a Alias) bool { return a.Name == alias }) {
+		return fmt.Errorf("%w: %s", ErrNotAnAlias, alias)
+	}
+	return bn.repository.Delete(bn.ref(alias))
+}
+
+func (bn *BuildNumber) Aliases() ([]Alias, error) {
+	refs, err := bn.repository.Refs(repository.WithPrefix(bn.refName+"/"), repository.WithSymbolic())
+	if err != nil {
+		return nil, err
+	}
+	aliases := []Alias{}
+	for _, ref := range refs {
+		if ref.Target == "" {
+			continue
+		}
+		aliases = append(aliases, Alias{Name: bn.namespace(ref), Target: strings.TrimPrefix(ref.Target, bn.refName+"/")})
+	}
+	return aliases, nil
+}
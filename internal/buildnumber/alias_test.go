@@ -0,0 +1,110 @@
+package buildnumber_test
+
+import (
+	"testing"
+
+	"github.com/anselstetter/git-build-number/internal/buildnumber"
+	"github.com/anselstetter/git-build-number/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlias(t *testing.T) {
+	t.Run("resolve", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+		_, _ = bn.Set("release/2026.10", user, email, 5)
+
+		err := bn.Alias("current-release", "release/2026.10")
+		assert.NoError(t, err)
+
+		entry, err := bn.Get("current-release", user, email, false)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), entry.Number)
+
+		entry, _, err = bn.Inc("current-release", user, email, true)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(6), entry.Number)
+
+		target, _ := bn.Get("release/2026.10", user, email, false)
+		assert.Equal(t, entry, target)
+
+		hash, err := bn.Hash("current-release", 5)
+		assert.NoError(t, err)
+		assert.Equal(t, entry.Hash, hash.Hash)
+
+		aliases, _ := bn.Aliases()
+		assert.Equal(t, []buildnumber.Alias{{Name: "current-release", Target: "release/2026.10"}}, aliases)
+
+		ns, _ := bn.Namespaces()
+		assert.Equal(t, []buildnumber.Namespace{
+			{Name: "current-release", Entry: *entry, Target: "release/2026.10"},
+			{Name: "release/2026.10", Entry: *entry},
+		}, ns)
+	})
+	t.Run("retarget", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+		_, _ = bn.Set("release/1", user, email, 1)
+		_, _ = bn.Set("release/2", user, email, 2)
+
+		_ = bn.Alias("current", "release/1")
+		err := bn.Alias("current", "release/2")
+		assert.NoError(t, err)
+
+		entry, _ := bn.Get("current", user, email, false)
+		assert.Equal(t, int64(2), entry.Number)
+	})
+	t.Run("existing namespace", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+		_, _ = bn.Set("release/1", user, email, 1)
+		_, _ = bn.Set("current", user, email, 2)
+
+		err := bn.Alias("current", "release/1")
+		assert.ErrorIs(t, err, buildnumber.ErrNamespaceExists)
+	})
+	t.Run("missing target", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		err := bn.Alias("current", "release/1")
+		assert.ErrorIs(t, err, buildnumber.ErrBuildNumberNotFound)
+	})
+	t.Run("dangling", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+		_, _ = bn.Set("release/1", user, email, 1)
+		_ = bn.Alias("current", "release/1")
+		_ = bn.Delete("release/1")
+
+		ns, err := bn.Namespaces()
+		assert.NoError(t, err)
+		assert.Equal(t, []buildnumber.Namespace{{Name: "current", Target: "release/1"}}, ns)
+	})
+}
+
+func TestUnalias(t *testing.T) {
+	repo, _, _ := repository.NewGitInMemoryRepository(true)
+	bn := buildnumber.New(repo)
+	entry, _ := bn.Set("release/1", user, email, 1)
+	_ = bn.Alias("current", "release/1")
+
+	err := bn.Unalias("release/1")
+	assert.ErrorIs(t, err, buildnumber.ErrNotAnAlias)
+
+	err = bn.Unalias("current")
+	assert.NoError(t, err)
+
+	ns, _ := bn.Namespaces()
+	assert.Equal(t, []buildnumber.Namespace{{Name: "release/1", Entry: *entry}}, ns)
+}
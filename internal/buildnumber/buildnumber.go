@@ -2,9 +2,12 @@ package buildnumber
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/anselstetter/git-build-number/internal/repository"
 )
@@ -18,9 +21,12 @@ var (
 	ErrInvalidFormat       = errors.New("format is invalid")
 )
 
+const remoteAttempts = 3
+
 type Namespace struct {
-	Name  string
-	Entry Entry
+	Name   string
+	Entry  Entry
+	Target string
 }
 
 type Entry struct {
@@ -29,9 +35,16 @@ type Entry struct {
 }
 
 type BuildNumber struct {
-	repository repository.Repository
-	fileName   string
-	refName    string
+	repository       repository.Repository
+	fileName         string
+	metadataFileName string
+	refName          string
+	rev              string
+}
+
+func (bn BuildNumber) At(rev string) BuildNumber {
+	bn.rev = rev
+	return bn
 }
 
 func (bn *BuildNumber) Hash(namespace string, number int64) (*Entry, error) {
@@ -41,7 +54,7 @@ func (bn *BuildNumber) Hash(namespace string, number int64) (*Entry, error) {
 	} else if err != nil {
 		return nil, err
 	}
-	if len(commits) != 1 {
+	if len(commits) == 0 {
 		return nil, ErrBuildNumberNotFound
 	}
 	commit := commits[0]
@@ -52,17 +65,34 @@ func (bn *BuildNumber) Hash(namespace string, number int64) (*Entry, error) {
 	return &entry, nil
 }
 
+func (bn *BuildNumber) Number(namespace string) (*Entry, error) {
+	head, err := bn.head()
+	if err != nil {
+		return nil, err
+	}
+	commits, err := bn.repository.Commits(bn.ref(namespace), repository.WithHeaderValue(head.Hash))
+	if err != nil && errors.Is(err, repository.ErrReferenceNotFound) {
+		return nil, errors.Join(err, ErrBuildNumberNotFound)
+	} else if err != nil {
+		return nil, err
+	}
+	if len(commits) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrBuildNumberNotFound, head.Hash)
+	}
+	return commitEntry(commits[0])
+}
+
 func (bn *BuildNumber) Get(namespace string, user string, email string, create bool) (*Entry, error) {
 	content, err := bn.repository.Content(bn.ref(namespace), bn.fileName)
 	if err != nil && errors.Is(err, repository.ErrReferenceNotFound) {
 		if !create {
 			return nil, errors.Join(err, ErrBuildNumberNotFound)
 		}
-		return bn.Set(namespace, user, email, 1)
+		return bn.Create(namespace, user, email, Seed{})
 	} else if err != nil {
 		return nil, err
 	}
-	entry, err := Unmarshal(*content)
+	entry, err := bn.unmarshal(*content)
 	if err != nil {
 		return nil, err
 	}
@@ -70,14 +100,15 @@ func (bn *BuildNumber) Get(namespace string, user string, email string, create b
 }
 
 func (bn *BuildNumber) Inc(namespace string, user string, email string, force bool) (*Entry, bool, error) {
+	if err := bn.frozen(namespace); err != nil {
+		return nil, false, err
+	}
 	entry, err := bn.Get(namespace, user, email, true)
 	if err != nil {
 		return nil, false, err
 	}
-	head, err := bn.repository.Head()
-	if err != nil && errors.Is(err, repository.ErrReferenceNotFound) {
-		return nil, false, errors.Join(err, ErrNoHead)
-	} else if err != nil {
+	head, err := bn.head()
+	if err != nil {
 		return nil, false, err
 	}
 	if head.Hash == entry.Hash && !force {
@@ -90,31 +121,111 @@ func (bn *BuildNumber) Inc(namespace string, user string, email string, force bo
 	return entry, true, nil
 }
 
-func (bn *BuildNumber) Set(namespace string, user string, email string, number int64) (*Entry, error) {
-	head, err := bn.repository.Head()
+func (bn *BuildNumber) Next(namespace string, force bool) (*Entry, bool, error) {
+	head, err := bn.head()
+	if err != nil {
+		return nil, false, err
+	}
+	entry, err := bn.Get(namespace, "", "", false)
+	if err != nil && errors.Is(err, ErrBuildNumberNotFound) {
+		entry, _, err = bn.seeded(namespace, Seed{})
+		if err != nil {
+			return nil, false, err
+		}
+	} else if err != nil {
+		return nil, false, err
+	}
+	entry, updated := next(*entry, head.Hash, force)
+	return entry, updated, nil
+}
+
+func (bn *BuildNumber) NextRemote(ctx context.Context, namespace string, remoteName string, force bool) (*Entry, bool, error) {
+	head, err := bn.head()
+	if err != nil {
+		return nil, false, err
+	}
+	trackingRef := bn.trackingRef(remoteName, namespace)
+
+	err = bn.repository.Fetch(ctx, bn.ref(namespace), remoteName, true, repository.WithDestination(trackingRef))
 	if err != nil && errors.Is(err, repository.ErrReferenceNotFound) {
-		return nil, errors.Join(err, ErrNoHead)
+		return bn.Next(namespace, force)
 	} else if err != nil {
+		return nil, false, err
+	}
+	entry, err := bn.entry(trackingRef)
+	if err != nil {
+		return nil, false, err
+	}
+	entry, updated := next(*entry, head.Hash, force)
+	return entry, updated, nil
+}
+
+func (bn *BuildNumber) ReadOnly() error {
+	return bn.repository.DryRun(func(repository.Change) {})
+}
+
+func (bn *BuildNumber) IncRemote(ctx context.Context, namespace string, remoteName string, user string, email string, force bool) (*Entry, bool, error) {
+	var err error
+	for range remoteAttempts {
+		err = bn.repository.Fetch(ctx, bn.ref(namespace), remoteName, true)
+		if err != nil && !errors.Is(err, repository.ErrReferenceNotFound) {
+			return nil, false, err
+		}
+		entry, updated, incErr := bn.Inc(namespace, user, email, force)
+		if incErr != nil {
+			return nil, false, incErr
+		}
+		err = bn.repository.Push(ctx, bn.ref(namespace), remoteName, false)
+		if err == nil {
+			return entry, updated, nil
+		}
+		if !errors.Is(err, repository.ErrReferenceChanged) {
+			return nil, false, err
+		}
+	}
+	return nil, false, err
+}
+
+func (bn *BuildNumber) Set(namespace string, user string, email string, number int64) (*Entry, error) {
+	if err := bn.frozen(namespace); err != nil {
+		return nil, err
+	}
+	head, err := bn.head()
+	if err != nil {
 		return nil, err
 	}
 	entry := Entry{
 		Number: number,
 		Hash:   head.Hash,
 	}
-	content, err := Marshal(entry)
+	msg := fmt.Sprintf("Set build number to %d for %s\n", entry.Number, entry.Hash)
+	err = bn.commit(namespace, entry, msg, repository.Author{Name: user, Email: email}, nil)
 	if err != nil {
 		return nil, err
 	}
-	msg := fmt.Sprintf("Set build number to %d for %s\n", number, head.Hash)
+	return &entry, nil
+}
 
-	_, err = bn.repository.Commit(bn.ref(namespace), bn.fileName, content, msg,
-		repository.WithAuthor(repository.Author{Name: user, Email: email}),
-		repository.WithHeaders([]repository.Header{{Key: strconv.FormatInt(number, 10), Value: head.Hash}}),
-	)
+func (bn *BuildNumber) commit(namespace string, entry Entry, msg string, author repository.Author, when *time.Time) error {
+	content, err := Marshal(entry)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	return &entry, nil
+	headers := []repository.Header{{Key: strconv.FormatInt(entry.Number, 10), Value: entry.Hash}}
+
+	if when != nil {
+		_, err = bn.repository.Commit(bn.ref(namespace), bn.fileName, content, msg,
+			repository.WithAuthor(author),
+			repository.WithHeaders(headers),
+			repository.WithTime(*when),
+		)
+		return err
+	}
+	_, err = bn.repository.Commit(bn.ref(namespace), bn.fileName, content, msg,
+		repository.WithAuthor(author),
+		repository.WithHeaders(headers),
+	)
+	return err
 }
 
 func (bn *BuildNumber) Delete(namespaces ...string) error {
@@ -141,39 +252,180 @@ func (bn *BuildNumber) Clear() error {
 	return nil
 }
 
+func (bn *BuildNumber) DeleteRemote(ctx context.Context, remoteName string, namespaces ...string) error {
+	for _, namespace := range namespaces {
+		err := bn.repository.DeleteRemote(ctx, bn.ref(namespace), remoteName)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (bn *BuildNumber) ClearRemote(ctx context.Context, remoteName string) error {
+	refs, err := bn.repository.RemoteRefs(ctx, remoteName, repository.WithPrefix(bn.refName+"/"))
+	if err != nil {
+		return err
+	}
+	for _, ref := range refs {
+		err := bn.repository.DeleteRemote(ctx, ref.Path, remoteName)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (bn *BuildNumber) Namespaces() ([]Namespace, error) {
-	refs, err := bn.repository.Refs(repository.WithPrefix(bn.refName))
+	refs, err := bn.repository.Refs(repository.WithPrefix(bn.refName), repository.WithSymbolic())
 	if err != nil {
 		return nil, err
 	}
 	namespaces := make([]Namespace, 0, len(refs))
 
 	for _, ref := range refs {
-		entry, err := bn.Get(ref.Name, "", "", false)
-		if err != nil {
+		namespace := Namespace{Name: bn.namespace(ref), Target: strings.TrimPrefix(ref.Target, bn.refName+"/")}
+		entry, err := bn.Get(namespace.Name, "", "", false)
+		if err != nil && namespace.Target != "" && errors.Is(err, ErrBuildNumberNotFound) {
+			namespaces = append(namespaces, namespace)
+			continue
+		} else if err != nil {
 			return nil, err
 		}
-		namespaces = append(namespaces, Namespace{Name: ref.Name, Entry: *entry})
+		namespace.Entry = *entry
+		namespaces = append(namespaces, namespace)
 	}
 	return namespaces, nil
 }
 
-func (bn *BuildNumber) Mirror(remoteName string) error {
-	return bn.repository.Mirror(fmt.Sprintf("%s/", bn.refName), remoteName)
+func (bn *BuildNumber) Mirror(ctx context.Context, remoteName string) error {
+	return bn.repository.Mirror(ctx, fmt.Sprintf("%s/", bn.refName), remoteName)
 }
 
-func (bn *BuildNumber) Push(remoteName string) error {
-	return bn.repository.Push(fmt.Sprintf("%s/*", bn.refName), remoteName, true)
+func (bn *BuildNumber) Push(ctx context.Context, remoteName string, filter Filter) error {
+	if filter.Empty() {
+		return bn.repository.Push(ctx, fmt.Sprintf("%s/*", bn.refName), remoteName, true)
+	}
+	refs, err := bn.repository.Refs(repository.WithPrefix(bn.refName + "/"))
+	if err != nil {
+		return err
+	}
+	for _, ref := range refs {
+		if !filter.Match(bn.namespace(ref)) {
+			continue
+		}
+		if err := bn.repository.Push(ctx, ref.Path, remoteName, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (bn *BuildNumber) Fetch(ctx context.Context, remoteName string, filter Filter, depth int) error {
+	return bn.fetch(ctx, remoteName, filter, depth, bn.ref)
 }
 
-func (bn *BuildNumber) Fetch(remoteName string) error {
-	return bn.repository.Fetch(fmt.Sprintf("%s/*", bn.refName), remoteName, true)
+func (bn *BuildNumber) FetchTracking(ctx context.Context, remoteName string, filter Filter, depth int) error {
+	return bn.fetch(ctx, remoteName, filter, depth, func(namespace string) string {
+		return bn.trackingRef(remoteName, namespace)
+	})
+}
+
+func (bn *BuildNumber) fetch(ctx context.Context, remoteName string, filter Filter, depth int, destination func(namespace string) string) error {
+	if filter.Empty() {
+		return bn.repository.Fetch(ctx, bn.ref("*"), remoteName, true, repository.WithDestination(destination("*")), repository.WithDepth(depth))
+	}
+	refs, err := bn.repository.RemoteRefs(ctx, remoteName, repository.WithPrefix(bn.refName+"/"))
+	if err != nil {
+		return err
+	}
+	for _, ref := range refs {
+		namespace := bn.namespace(ref)
+		if !filter.Match(namespace) {
+			continue
+		}
+		if err := bn.repository.Fetch(ctx, ref.Path, remoteName, true, repository.WithDestination(destination(namespace)), repository.WithDepth(depth)); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (bn *BuildNumber) ref(namespace string) string {
 	return fmt.Sprintf("%s/%s", bn.refName, namespace)
 }
 
+func (bn *BuildNumber) namespace(ref repository.Ref) string {
+	return strings.TrimPrefix(ref.Path, bn.refName+"/")
+}
+
+func (bn *BuildNumber) trackingRef(remoteName string, namespace string) string {
+	return fmt.Sprintf("refs/remotes/%s/%s/%s", remoteName, strings.TrimPrefix(bn.refName, "refs/"), namespace)
+}
+
+func (bn *BuildNumber) head() (*repository.Ref, error) {
+	if bn.rev != "" {
+		hash, err := bn.repository.Resolve(bn.rev)
+		if err != nil {
+			return nil, err
+		}
+		return &repository.Ref{Name: bn.rev, Hash: hash}, nil
+	}
+	head, err := bn.repository.Head()
+	if err != nil && errors.Is(err, repository.ErrReferenceNotFound) {
+		return nil, errors.Join(err, ErrNoHead)
+	} else if err != nil {
+		return nil, err
+	}
+	return head, nil
+}
+
+func (bn *BuildNumber) lastBuild(hash string) (*repository.Commit, error) {
+	commit, err := bn.repository.Lookup(hash)
+	if err != nil {
+		return nil, err
+	}
+	for len(commit.Parents) > 0 && len(commit.Headers) > 0 {
+		parent, err := bn.repository.Lookup(commit.Parents[0])
+		if err != nil && errors.Is(err, repository.ErrObjectNotFound) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if len(parent.Headers) == 0 || parent.Headers[0] != commit.Headers[0] {
+			break
+		}
+		commit = parent
+	}
+	return commit, nil
+}
+
+func next(entry Entry, hash string, force bool) (*Entry, bool) {
+	if entry.Hash == hash && !force {
+		return &entry, false
+	}
+	next := Entry{
+		Number: entry.Number + 1,
+		Hash:   hash,
+	}
+	return &next, true
+}
+
+func (bn *BuildNumber) unmarshal(content []byte) (*Entry, error) {
+	entry, err := Unmarshal(content)
+	if err != nil {
+		return nil, err
+	}
+	format, err := bn.repository.ObjectFormat()
+	if err != nil {
+		return nil, err
+	}
+	if !format.Valid(entry.Hash) {
+		return nil, fmt.Errorf("%w: %s is not a %s hash", ErrInvalidHash, entry.Hash, format)
+	}
+	return entry, nil
+}
+
 func Marshal(entry Entry) ([]byte, error) {
 	if entry.Number == int64(0) {
 		return nil, ErrZeroBuildNumber
@@ -204,8 +456,9 @@ func Unmarshal(data []byte) (*Entry, error) {
 
 func New(repository repository.Repository) BuildNumber {
 	return BuildNumber{
-		repository: repository,
-		fileName:   "build-number",
-		refName:    "refs/build-number",
+		repository:       repository,
+		fileName:         "build-number",
+		metadataFileName: "metadata",
+		refName:          "refs/build-number",
 	}
 }
@@ -1,11 +1,15 @@
 package buildnumber_test
 
 import (
+	"context"
+	"errors"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/anselstetter/git-build-number/internal/buildnumber"
 	"github.com/anselstetter/git-build-number/internal/repository"
+	"github.com/anselstetter/git-build-number/internal/repository/repositorytest"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -46,6 +50,19 @@ func TestGet(t *testing.T) {
 		assert.ErrorIs(t, err, buildnumber.ErrBuildNumberNotFound)
 		assert.Nil(t, entry)
 	})
+	t.Run("corrupt blob", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bnRepo := buildnumber.New(repo)
+		_, _ = bnRepo.Set("test", user, email, 5)
+		fake := repositorytest.NewFake(repo).Corrupt([]byte("garbage"))
+		bn := buildnumber.New(fake)
+
+		entry, err := bn.Get("test", user, email, false)
+		assert.ErrorIs(t, err, buildnumber.ErrInvalidFormat)
+		assert.Nil(t, entry)
+	})
 }
 
 func TestSet(t *testing.T) {
@@ -115,7 +132,7 @@ func TestInc(t *testing.T) {
 		bnRemote := buildnumber.New(remote)
 
 		_, _ = bnRemote.Set("test", user, email, 123)
-		_ = bnLocal.Fetch("origin")
+		_ = bnLocal.Fetch(t.Context(), "origin", buildnumber.Filter{}, 0)
 
 		entry, updated, err := bnLocal.Inc("test", user, email, false)
 		assert.NoError(t, err)
@@ -124,6 +141,238 @@ func TestInc(t *testing.T) {
 	})
 }
 
+func TestNext(t *testing.T) {
+	t.Run("missing namespace", func(t *testing.T) {
+		t.Parallel()
+
+		repo, head, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		entry, updated, err := bn.Next("test", false)
+		assert.NoError(t, err)
+		assert.Equal(t, buildnumber.Entry{Number: 1, Hash: head.Hash}, *entry)
+		assert.False(t, updated)
+
+		_, err = bn.Get("test", user, email, false)
+		assert.ErrorIs(t, err, buildnumber.ErrBuildNumberNotFound)
+	})
+	t.Run("head already set", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+		_, _ = bn.Set("test", user, email, 5)
+
+		entry, updated, err := bn.Next("test", false)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), entry.Number)
+		assert.False(t, updated)
+	})
+	t.Run("matches inc", func(t *testing.T) {
+		t.Parallel()
+
+		for _, force := range []bool{false, true} {
+			repo, _, _ := repository.NewGitInMemoryRepository(true)
+			bn := buildnumber.New(repo)
+
+			next, _, err := bn.Next("test", force)
+			assert.NoError(t, err)
+
+			entry, _, err := bn.Inc("test", user, email, force)
+			assert.NoError(t, err)
+			assert.Equal(t, entry, next)
+		}
+	})
+	t.Run("no head", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(false)
+		bn := buildnumber.New(repo)
+
+		entry, updated, err := bn.Next("test", false)
+		assert.Nil(t, entry)
+		assert.ErrorIs(t, err, buildnumber.ErrNoHead)
+		assert.False(t, updated)
+	})
+}
+
+func TestNextRemote(t *testing.T) {
+	t.Run("remote ahead", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		remote := addRemote(t, "origin", true, repo)
+
+		bnLocal := buildnumber.New(repo)
+		bnRemote := buildnumber.New(remote)
+
+		_, _ = bnLocal.Set("test", user, email, 3)
+		_, _ = bnRemote.Set("test", user, email, 10)
+		_, _ = repo.Commit("refs/heads/main", "next", []byte("next"), "Next commit", repository.WithHead())
+
+		entry, updated, err := bnLocal.NextRemote(t.Context(), "test", "origin", false)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(11), entry.Number)
+		assert.True(t, updated)
+
+		local, err := bnLocal.Get("test", user, email, false)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), local.Number)
+	})
+	t.Run("missing on remote", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		_ = addRemote(t, "origin", true, repo)
+
+		bn := buildnumber.New(repo)
+		_, _ = bn.Set("test", user, email, 3)
+
+		entry, updated, err := bn.NextRemote(t.Context(), "test", "origin", false)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), entry.Number)
+		assert.False(t, updated)
+	})
+	t.Run("without remote", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		entry, _, err := bn.NextRemote(t.Context(), "test", "origin", false)
+		assert.Nil(t, entry)
+		assert.ErrorIs(t, err, repository.ErrRemoteNotFound)
+	})
+}
+
+func TestIncRemote(t *testing.T) {
+	first := strings.Repeat("1", 40)
+	second := strings.Repeat("2", 40)
+
+	t.Run("empty remote", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(false)
+		repo.SetUnknownRevisions(true)
+		remote := addRemote(t, "origin", false, repo)
+
+		bn := buildnumber.New(repo).At(first)
+		entry, updated, err := bn.IncRemote(t.Context(), "test", "origin", user, email, false)
+		assert.NoError(t, err)
+		assert.Equal(t, &buildnumber.Entry{Number: 1, Hash: first}, entry)
+		assert.False(t, updated)
+
+		bnRemote := buildnumber.New(remote)
+		entry, err = bnRemote.Get("test", user, email, false)
+		assert.NoError(t, err)
+		assert.Equal(t, &buildnumber.Entry{Number: 1, Hash: first}, entry)
+	})
+	t.Run("remote ahead", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(false)
+		repo.SetUnknownRevisions(true)
+		remote := addRemote(t, "origin", true, repo)
+
+		bnRemote := buildnumber.New(remote)
+		_, _ = bnRemote.Set("test", user, email, 10)
+
+		bn := buildnumber.New(repo).At(first)
+		entry, updated, err := bn.IncRemote(t.Context(), "test", "origin", user, email, false)
+		assert.NoError(t, err)
+		assert.Equal(t, &buildnumber.Entry{Number: 11, Hash: first}, entry)
+		assert.True(t, updated)
+
+		entry, err = bnRemote.Get("test", user, email, false)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(11), entry.Number)
+	})
+	t.Run("retries when the remote changed", func(t *testing.T) {
+		t.Parallel()
+
+		remote, path := repositorytest.NewRemote(t, true)
+		repo, _, _ := repository.NewGitInMemoryRepository(false)
+		repo.SetUnknownRevisions(true)
+		_ = repo.AddRemote("origin", path)
+		racing := repositorytest.NewFake(repo)
+		racing.Before("Push", repositorytest.ConcurrentUpdate(t, path, "refs/build-number/test", func(other repository.Repository) {
+			bnOther := buildnumber.New(other).At(second)
+			_, _, err := bnOther.Inc("test", user, email, false)
+			assert.NoError(t, err)
+		}))
+
+		bn := buildnumber.New(racing).At(first)
+		entry, updated, err := bn.IncRemote(t.Context(), "test", "origin", user, email, false)
+		assert.NoError(t, err)
+		assert.Equal(t, &buildnumber.Entry{Number: 2, Hash: first}, entry)
+		assert.True(t, updated)
+
+		bnRemote := buildnumber.New(remote)
+		entry, err = bnRemote.Get("test", user, email, false)
+		assert.NoError(t, err)
+		assert.Equal(t, &buildnumber.Entry{Number: 2, Hash: first}, entry)
+		assert.Equal(t, 2, racing.Calls("Push"))
+	})
+	t.Run("gives up when the remote keeps changing", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(false)
+		repo.SetUnknownRevisions(true)
+		_ = addRemote(t, "origin", true, repo)
+		fake := repositorytest.NewFake(repo).Fail("Push", repository.ErrReferenceChanged)
+
+		bn := buildnumber.New(fake).At(first)
+		entry, _, err := bn.IncRemote(t.Context(), "test", "origin", user, email, false)
+		assert.Nil(t, entry)
+		assert.ErrorIs(t, err, repository.ErrReferenceChanged)
+		assert.Equal(t, 3, fake.Calls("Push"))
+	})
+	t.Run("canceled", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(false)
+		repo.SetUnknownRevisions(true)
+		remote := addRemote(t, "origin", true, repo)
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		bn := buildnumber.New(repo).At(first)
+		entry, _, err := bn.IncRemote(ctx, "test", "origin", user, email, false)
+		assert.Nil(t, entry)
+		assert.ErrorIs(t, err, context.Canceled)
+
+		refs, _ := repo.Refs(repository.WithPrefix("refs/build-number/"))
+		assert.Empty(t, refs)
+		refs, _ = remote.Refs(repository.WithPrefix("refs/build-number/"))
+		assert.Empty(t, refs)
+	})
+	t.Run("push fails", func(t *testing.T) {
+		t.Parallel()
+
+		errPush := errors.New("connection reset")
+		repo, _, _ := repository.NewGitInMemoryRepository(false)
+		repo.SetUnknownRevisions(true)
+		_ = addRemote(t, "origin", true, repo)
+		fake := repositorytest.NewFake(repo).FailOnce("Push", errPush)
+
+		bn := buildnumber.New(fake).At(first)
+		entry, _, err := bn.IncRemote(t.Context(), "test", "origin", user, email, false)
+		assert.Nil(t, entry)
+		assert.ErrorIs(t, err, errPush)
+		assert.Equal(t, 1, fake.Calls("Push"))
+	})
+	t.Run("without remote", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		entry, _, err := bn.IncRemote(t.Context(), "test", "origin", user, email, false)
+		assert.Nil(t, entry)
+		assert.ErrorIs(t, err, repository.ErrRemoteNotFound)
+	})
+}
+
 func TestDelete(t *testing.T) {
 	repo, _, _ := repository.NewGitInMemoryRepository(true)
 	bn := buildnumber.New(repo)
@@ -152,6 +401,71 @@ func TestClear(t *testing.T) {
 	assert.Equal(t, []buildnumber.Namespace{}, ns)
 }
 
+func TestDeleteRemote(t *testing.T) {
+	t.Run("without remote", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		err := bn.DeleteRemote(t.Context(), "origin", "test")
+		assert.ErrorIs(t, err, repository.ErrRemoteNotFound)
+	})
+	t.Run("with invalid namespace", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		_ = addRemote(t, "origin", true, repo)
+		bn := buildnumber.New(repo)
+
+		err := bn.DeleteRemote(t.Context(), "origin", "invalid")
+		assert.ErrorIs(t, err, repository.ErrReferenceNotFound)
+	})
+	t.Run("with remote", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		remote := addRemote(t, "origin", true, repo)
+
+		bnLocal := buildnumber.New(repo)
+		bnRemote := buildnumber.New(remote)
+
+		_, _ = bnRemote.Set("test", user, email, 1)
+		entry, _ := bnRemote.Set("test2", user, email, 2)
+		local, _ := bnLocal.Set("test", user, email, 3)
+
+		err := bnLocal.DeleteRemote(t.Context(), "origin", "test")
+		assert.NoError(t, err)
+
+		ns, _ := bnRemote.Namespaces()
+		assert.Equal(t, []buildnumber.Namespace{{Name: "test2", Entry: *entry}}, ns)
+
+		ns, _ = bnLocal.Namespaces()
+		assert.Equal(t, []buildnumber.Namespace{{Name: "test", Entry: *local}}, ns)
+	})
+}
+
+func TestClearRemote(t *testing.T) {
+	repo, _, _ := repository.NewGitInMemoryRepository(true)
+	remote := addRemote(t, "origin", true, repo)
+
+	bnLocal := buildnumber.New(repo)
+	bnRemote := buildnumber.New(remote)
+
+	_, _ = bnRemote.Set("test", user, email, 1)
+	_, _ = bnRemote.Set("release/1", user, email, 2)
+	local, _ := bnLocal.Set("test", user, email, 3)
+
+	err := bnLocal.ClearRemote(t.Context(), "origin")
+	assert.NoError(t, err)
+
+	ns, _ := bnRemote.Namespaces()
+	assert.Equal(t, []buildnumber.Namespace{}, ns)
+
+	ns, _ = bnLocal.Namespaces()
+	assert.Equal(t, []buildnumber.Namespace{{Name: "test", Entry: *local}}, ns)
+}
+
 func TestNamespaces(t *testing.T) {
 	repo, ref, _ := repository.NewGitInMemoryRepository(true)
 	bn := buildnumber.New(repo)
@@ -164,16 +478,16 @@ func TestNamespaces(t *testing.T) {
 
 	expected := []buildnumber.Namespace{
 		{
-			Name: "test",
+			Name: "other",
 			Entry: buildnumber.Entry{
-				Number: 123,
+				Number: 321,
 				Hash:   ref.Hash,
 			},
 		},
 		{
-			Name: "other",
+			Name: "test",
 			Entry: buildnumber.Entry{
-				Number: 321,
+				Number: 123,
 				Hash:   ref.Hash,
 			},
 		},
@@ -207,6 +521,19 @@ func TestHash(t *testing.T) {
 		assert.ErrorIs(t, err, buildnumber.ErrBuildNumberNotFound)
 		assert.Nil(t, hash)
 	})
+	t.Run("not in history", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		_, _ = bn.Set("test", user, email, 1)
+		_, _ = bn.Set("test", user, email, 2)
+
+		hash, err := bn.Hash("test", 321)
+		assert.ErrorIs(t, err, buildnumber.ErrBuildNumberNotFound)
+		assert.Nil(t, hash)
+	})
 	t.Run("no ref found", func(t *testing.T) {
 		t.Parallel()
 
@@ -226,7 +553,7 @@ func TestPush(t *testing.T) {
 		repo, _, _ := repository.NewGitInMemoryRepository(true)
 		bn := buildnumber.New(repo)
 
-		err := bn.Push("origin")
+		err := bn.Push(t.Context(), "origin", buildnumber.Filter{})
 		assert.ErrorIs(t, err, repository.ErrRemoteNotFound)
 	})
 	t.Run("with remote", func(t *testing.T) {
@@ -240,12 +567,31 @@ func TestPush(t *testing.T) {
 
 		_, _ = bnLocal.Set("test", user, email, 123)
 
-		err := bnLocal.Push("origin")
+		err := bnLocal.Push(t.Context(), "origin", buildnumber.Filter{})
 		assert.NoError(t, err)
 
 		entry, _ := bnRemote.Get("test", user, email, false)
 		assert.Equal(t, entry.Number, int64(123))
 	})
+	t.Run("with filter", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		remote := addRemote(t, "origin", false, repo)
+
+		bnLocal := buildnumber.New(repo)
+		bnRemote := buildnumber.New(remote)
+
+		_, _ = bnLocal.Set("pr/1", user, email, 1)
+		_, _ = bnLocal.Set("pr/2", user, email, 2)
+		_, _ = bnLocal.Set("prod", user, email, 3)
+
+		err := bnLocal.Push(t.Context(), "origin", buildnumber.Filter{Include: []string{"pr/*"}, Exclude: []string{"pr/2"}})
+		assert.NoError(t, err)
+
+		namespaces, _ := bnRemote.Namespaces()
+		assert.Equal(t, []buildnumber.Namespace{{Name: "pr/1", Entry: buildnumber.Entry{Number: 1, Hash: namespaces[0].Entry.Hash}}}, namespaces)
+	})
 }
 
 func TestFetch(t *testing.T) {
@@ -255,7 +601,7 @@ func TestFetch(t *testing.T) {
 		repo, _, _ := repository.NewGitInMemoryRepository(true)
 		bn := buildnumber.New(repo)
 
-		err := bn.Fetch("origin")
+		err := bn.Fetch(t.Context(), "origin", buildnumber.Filter{}, 0)
 		assert.ErrorIs(t, err, repository.ErrRemoteNotFound)
 	})
 	t.Run("with remote", func(t *testing.T) {
@@ -269,12 +615,34 @@ func TestFetch(t *testing.T) {
 
 		_, _ = bnRemote.Set("test", user, email, 123)
 
-		err := bnLocal.Fetch("origin")
+		err := bnLocal.Fetch(t.Context(), "origin", buildnumber.Filter{}, 0)
 		assert.NoError(t, err)
 
 		entry, _ := bnLocal.Get("test", user, email, false)
 		assert.Equal(t, entry.Number, int64(123))
 	})
+	t.Run("with filter", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		remote := addRemote(t, "origin", true, repo)
+
+		bnLocal := buildnumber.New(repo)
+		bnRemote := buildnumber.New(remote)
+
+		_, _ = bnRemote.Set("release/2026/10", user, email, 1)
+		_, _ = bnRemote.Set("dev", user, email, 2)
+
+		err := bnLocal.Fetch(t.Context(), "origin", buildnumber.Filter{Include: []string{"release/**"}}, 0)
+		assert.NoError(t, err)
+
+		entry, err := bnLocal.Get("release/2026/10", user, email, false)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), entry.Number)
+
+		_, err = bnLocal.Get("dev", user, email, false)
+		assert.ErrorIs(t, err, buildnumber.ErrBuildNumberNotFound)
+	})
 }
 
 func TestMirror(t *testing.T) {
@@ -282,7 +650,7 @@ func TestMirror(t *testing.T) {
 		repo, _, _ := repository.NewGitInMemoryRepository(true)
 		bn := buildnumber.New(repo)
 
-		err := bn.Mirror("origin")
+		err := bn.Mirror(t.Context(), "origin")
 		assert.ErrorIs(t, err, repository.ErrRemoteNotFound)
 	})
 	t.Run("with remote", func(t *testing.T) {
@@ -296,7 +664,7 @@ func TestMirror(t *testing.T) {
 		entry1, _ := bnLocal.Set("local-1", user, email, 123)
 		entry2, _ := bnLocal.Set("local-2", user, email, 123)
 
-		err := bnLocal.Mirror("origin")
+		err := bnLocal.Mirror(t.Context(), "origin")
 		assert.NoError(t, err)
 
 		ns, _ := bnRemote.Namespaces()
@@ -357,3 +725,126 @@ func TestUnmarshal(t *testing.T) {
 		assert.Nil(t, entry)
 	})
 }
+
+func TestAt(t *testing.T) {
+	t.Run("set", func(t *testing.T) {
+		t.Parallel()
+
+		repo, initial, _ := repository.NewGitInMemoryRepository(true)
+		_, _ = repo.Commit("refs/heads/main", "next", []byte("next"), "Next commit", repository.WithHead())
+		bn := buildnumber.New(repo).At("HEAD~1")
+
+		entry, err := bn.Set("test", user, email, 5)
+		assert.NoError(t, err)
+		assert.Equal(t, &buildnumber.Entry{Number: 5, Hash: initial.Hash}, entry)
+	})
+	t.Run("inc", func(t *testing.T) {
+		t.Parallel()
+
+		repo, initial, _ := repository.NewGitInMemoryRepository(true)
+		head, _ := repo.Commit("refs/heads/main", "next", []byte("next"), "Next commit", repository.WithHead())
+		bn := buildnumber.New(repo)
+		at := bn.At(initial.Hash[:7])
+
+		_, _ = bn.Set("test", user, email, 5)
+
+		entry, updated, err := at.Inc("test", user, email, false)
+		assert.NoError(t, err)
+		assert.True(t, updated)
+		assert.Equal(t, &buildnumber.Entry{Number: 6, Hash: initial.Hash}, entry)
+
+		entry, updated, err = at.Inc("test", user, email, false)
+		assert.NoError(t, err)
+		assert.False(t, updated)
+		assert.Equal(t, int64(6), entry.Number)
+
+		entry, _, err = bn.Next("test", false)
+		assert.NoError(t, err)
+		assert.Equal(t, &buildnumber.Entry{Number: 7, Hash: head.Hash}, entry)
+	})
+	t.Run("unknown revision", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo).At("missing")
+
+		entry, err := bn.Set("test", user, email, 5)
+		assert.ErrorIs(t, err, repository.ErrRevisionNotFound)
+		assert.Nil(t, entry)
+	})
+}
+
+func TestNumber(t *testing.T) {
+	t.Run("found", func(t *testing.T) {
+		t.Parallel()
+
+		repo, initial, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+		_, _ = bn.Set("test", user, email, 5)
+		_, _ = repo.Commit("refs/heads/main", "next", []byte("next"), "Next commit", repository.WithHead())
+		_, _, _ = bn.Inc("test", user, email, false)
+
+		at := bn.At(initial.Hash)
+		entry, err := at.Number("test")
+		assert.NoError(t, err)
+		assert.Equal(t, &buildnumber.Entry{Number: 5, Hash: initial.Hash}, entry)
+
+		entry, err = bn.Number("test")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(6), entry.Number)
+	})
+	t.Run("not numbered", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+		_, _ = bn.Set("test", user, email, 5)
+		_, _ = repo.Commit("refs/heads/main", "next", []byte("next"), "Next commit", repository.WithHead())
+
+		entry, err := bn.Number("test")
+		assert.ErrorIs(t, err, buildnumber.ErrBuildNumberNotFound)
+		assert.Nil(t, entry)
+	})
+	t.Run("missing namespace", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		entry, err := bn.Number("test")
+		assert.ErrorIs(t, err, buildnumber.ErrBuildNumberNotFound)
+		assert.Nil(t, entry)
+	})
+}
+
+func TestObjectFormat(t *testing.T) {
+	t.Run("sha256", func(t *testing.T) {
+		t.Parallel()
+
+		repo, head, _ := repository.NewGitInMemoryRepositoryWithFormat(repository.SHA256, true)
+		bn := buildnumber.New(repo)
+
+		entry, err := bn.Set("test", user, email, 5)
+		assert.NoError(t, err)
+		assert.Equal(t, &buildnumber.Entry{Number: 5, Hash: head.Hash}, entry)
+
+		entry, err = bn.Get("test", user, email, false)
+		assert.NoError(t, err)
+		assert.Equal(t, &buildnumber.Entry{Number: 5, Hash: head.Hash}, entry)
+
+		hash, err := bn.Hash("test", 5)
+		assert.NoError(t, err)
+		assert.Equal(t, head.Hash, hash.Hash)
+	})
+	t.Run("mismatching hash", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepositoryWithFormat(repository.SHA256, true)
+		_, _ = repo.Commit("refs/build-number/test", "build-number", []byte("5 0123456789abcdef0123456789abcdef01234567"), "commit")
+		bn := buildnumber.New(repo)
+
+		entry, err := bn.Get("test", user, email, false)
+		assert.ErrorIs(t, err, buildnumber.ErrInvalidHash)
+		assert.Nil(t, entry)
+	})
+}
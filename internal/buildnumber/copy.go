@@ -0,0 +1,124 @@
+package buildnumber
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/anselstetter/git-build-number/internal/repository"
+)
+
+var (
+	ErrNamespaceExists = errors.New("namespace already exists")
+)
+
+func (bn *BuildNumber) Copy(ctx context.Context, src string, dst string, user string, email string, history bool, remoteName string) (*Entry, error) {
+	if err := bn.absentRemote(ctx, remoteName, dst); err != nil {
+		return nil, err
+	}
+	msg := "Copy build number %d for %s from %s\n"
+	entry, err := bn.copy(src, dst, user, email, history, msg)
+	if err != nil {
+		return nil, err
+	}
+	if remoteName == "" {
+		return entry, nil
+	}
+	if err := bn.repository.Push(ctx, bn.ref(dst), remoteName, false); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (bn *BuildNumber) Rename(ctx context.Context, from string, to string, user string, email string, remoteName string) (*Entry, error) {
+	protected, err := bn.Protected(from)
+	if err != nil {
+		return nil, err
+	}
+	if remoteName != "" {
+		protectedRemote, err := bn.ProtectedRemote(ctx, remoteName, Filter{Include: []string{from}})
+		if err != nil {
+			return nil, err
+		}
+		protected = append(protected, protectedRemote...)
+	}
+	if len(protected) > 0 {
+		return nil, fmt.Errorf("%w: %s (unprotect it before renaming)", ErrProtected, from)
+	}
+	if err := bn.absentRemote(ctx, remoteName, to); err != nil {
+		return nil, err
+	}
+	msg := "Rename build number %d for %s from %s\n"
+	entry, err := bn.copy(from, to, user, email, true, msg)
+	if err != nil {
+		return nil, err
+	}
+	if remoteName != "" {
+		if err := bn.repository.Push(ctx, bn.ref(to), remoteName, false); err != nil {
+			return nil, errors.Join(err, bn.Delete(to))
+		}
+		err = bn.DeleteRemote(ctx, remoteName, from)
+		if err != nil && !errors.Is(err, repository.ErrReferenceNotFound) {
+			return nil, err
+		}
+	}
+	aliases, err := bn.Aliases()
+	if err != nil {
+		return nil, err
+	}
+	for _, alias := range aliases {
+		if alias.Target != from {
+			continue
+		}
+		if err := bn.repository.Alias(bn.ref(alias.Name), bn.ref(to)); err != nil {
+			return nil, err
+		}
+	}
+	if err := bn.Delete(from); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (bn *BuildNumber) absentRemote(ctx context.Context, remoteName string, namespace string) error {
+	if remoteName == "" {
+		return nil
+	}
+	refs, err := bn.repository.RemoteRefs(ctx, remoteName, repository.WithPrefix(bn.ref(namespace)))
+	if err != nil && !errors.Is(err, repository.ErrReferenceNotFound) {
+		return err
+	}
+	for _, ref := range refs {
+		if ref.Path == bn.ref(namespace) {
+			return fmt.Errorf("%w on %s: %s", ErrNamespaceExists, remoteName, namespace)
+		}
+	}
+	return nil
+}
+
+func (bn *BuildNumber) copy(src string, dst string, user string, email string, history bool, msg string) (*Entry, error) {
+	_, err := bn.entry(bn.ref(dst))
+	if err == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNamespaceExists, dst)
+	} else if !errors.Is(err, ErrBuildNumberNotFound) {
+		return nil, err
+	}
+	entry, err := bn.Get(src, "", "", false)
+	if err != nil {
+		return nil, err
+	}
+	if history {
+		hash, err := bn.repository.Resolve(bn.ref(src))
+		if err != nil {
+			return nil, err
+		}
+		if err := bn.repository.Update(bn.ref(dst), hash, ""); err != nil {
+			return nil, err
+		}
+	}
+	msg = fmt.Sprintf(msg, entry.Number, entry.Hash, src)
+	if err := bn.commit(dst, *entry, msg, repository.Author{Name: user, Email: email}, nil); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
@@ -0,0 +1,220 @@
+package buildnumber_test
+
+import (
+	"testing"
+
+	"github.com/anselstetter/git-build-number/internal/buildnumber"
+	"github.com/anselstetter/git-build-number/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCopy(t *testing.T) {
+	t.Run("with history", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		_, _ = bn.Set("prod", user, email, 1)
+		_, _ = bn.Set("prod", user, email, 2)
+
+		entry, err := bn.Copy(t.Context(), "prod", "hotfix", user, email, true, "")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), entry.Number)
+
+		commits, _ := repo.Commits("refs/build-number/hotfix")
+		assert.Len(t, commits, 3)
+		assert.Equal(t, "Copy build number 2 for "+entry.Hash+" from prod\n", commits[0].Message)
+
+		hash, err := bn.Hash("hotfix", 1)
+		assert.NoError(t, err)
+		assert.Equal(t, entry.Hash, hash.Hash)
+
+		source, _ := bn.Get("prod", "", "", false)
+		assert.Equal(t, entry, source)
+	})
+	t.Run("seed only", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		_, _ = bn.Set("prod", user, email, 1)
+		_, _ = bn.Set("prod", user, email, 2)
+
+		entry, err := bn.Copy(t.Context(), "prod", "hotfix", user, email, false, "")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), entry.Number)
+
+		commits, _ := repo.Commits("refs/build-number/hotfix")
+		assert.Len(t, commits, 1)
+	})
+	t.Run("existing destination", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		_, _ = bn.Set("prod", user, email, 1)
+		_, _ = bn.Set("hotfix", user, email, 5)
+
+		_, err := bn.Copy(t.Context(), "prod", "hotfix", user, email, true, "")
+		assert.ErrorIs(t, err, buildnumber.ErrNamespaceExists)
+	})
+	t.Run("missing source", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		_, err := bn.Copy(t.Context(), "prod", "hotfix", user, email, true, "")
+		assert.ErrorIs(t, err, buildnumber.ErrBuildNumberNotFound)
+	})
+	t.Run("with remote", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		remote := addRemote(t, "origin", true, repo)
+
+		bnLocal := buildnumber.New(repo)
+		bnRemote := buildnumber.New(remote)
+
+		_, _ = bnLocal.Set("prod", user, email, 3)
+
+		_, err := bnLocal.Copy(t.Context(), "prod", "hotfix", user, email, true, "origin")
+		assert.NoError(t, err)
+
+		entry, err := bnRemote.Get("hotfix", "", "", false)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), entry.Number)
+	})
+	t.Run("existing remote destination", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		remote := addRemote(t, "origin", true, repo)
+
+		bnLocal := buildnumber.New(repo)
+		bnRemote := buildnumber.New(remote)
+
+		_, _ = bnLocal.Set("prod", user, email, 3)
+		_, _ = bnRemote.Set("hotfix", user, email, 9)
+
+		_, err := bnLocal.Copy(t.Context(), "prod", "hotfix", user, email, true, "origin")
+		assert.ErrorIs(t, err, buildnumber.ErrNamespaceExists)
+
+		_, err = bnLocal.Get("hotfix", "", "", false)
+		assert.ErrorIs(t, err, buildnumber.ErrBuildNumberNotFound)
+
+		entry, err := bnRemote.Get("hotfix", "", "", false)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(9), entry.Number)
+	})
+}
+
+func TestRename(t *testing.T) {
+	t.Run("local", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		_, _ = bn.Set("staging", user, email, 1)
+		_, _ = bn.Set("staging", user, email, 2)
+
+		entry, err := bn.Rename(t.Context(), "staging", "preprod", user, email, "")
+		assert.NoError(t, err)
+
+		ns, _ := bn.Namespaces()
+		assert.Equal(t, []buildnumber.Namespace{{Name: "preprod", Entry: *entry}}, ns)
+
+		commits, _ := repo.Commits("refs/build-number/preprod")
+		assert.Len(t, commits, 3)
+		assert.Equal(t, "Rename build number 2 for "+entry.Hash+" from staging\n", commits[0].Message)
+	})
+	t.Run("with remote", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		remote := addRemote(t, "origin", true, repo)
+
+		bnLocal := buildnumber.New(repo)
+		bnRemote := buildnumber.New(remote)
+
+		_, _ = bnLocal.Set("staging", user, email, 4)
+		_ = bnLocal.Push(t.Context(), "origin", buildnumber.Filter{})
+
+		entry, err := bnLocal.Rename(t.Context(), "staging", "preprod", user, email, "origin")
+		assert.NoError(t, err)
+
+		ns, _ := bnRemote.Namespaces()
+		assert.Equal(t, []buildnumber.Namespace{{Name: "preprod", Entry: *entry}}, ns)
+	})
+	t.Run("existing remote destination", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		remote := addRemote(t, "origin", true, repo)
+
+		bnLocal := buildnumber.New(repo)
+		bnRemote := buildnumber.New(remote)
+
+		staging, _ := bnLocal.Set("staging", user, email, 4)
+		_, _ = bnRemote.Set("preprod", user, email, 9)
+
+		_, err := bnLocal.Rename(t.Context(), "staging", "preprod", user, email, "origin")
+		assert.ErrorIs(t, err, buildnumber.ErrNamespaceExists)
+
+		ns, _ := bnLocal.Namespaces()
+		assert.Equal(t, []buildnumber.Namespace{{Name: "staging", Entry: *staging}}, ns)
+
+		entry, err := bnRemote.Get("preprod", "", "", false)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(9), entry.Number)
+	})
+	t.Run("moves aliases", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		_, _ = bn.Set("staging", user, email, 2)
+		_ = bn.Alias("next", "staging")
+
+		_, err := bn.Rename(t.Context(), "staging", "preprod", user, email, "")
+		assert.NoError(t, err)
+
+		aliases, err := bn.Aliases()
+		assert.NoError(t, err)
+		assert.Equal(t, []buildnumber.Alias{{Name: "next", Target: "preprod"}}, aliases)
+
+		entry, err := bn.Get("next", "", "", false)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), entry.Number)
+	})
+	t.Run("protected", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		remote := addRemote(t, "origin", true, repo)
+
+		bnLocal := buildnumber.New(repo)
+		bnRemote := buildnumber.New(remote)
+
+		_, _ = bnLocal.Set("staging", user, email, 4)
+		_, _ = bnRemote.Set("staging", user, email, 4)
+		_ = bnRemote.SetMetadata("staging", user, email, buildnumber.Metadata{Protected: true})
+
+		_, err := bnLocal.Rename(t.Context(), "staging", "preprod", user, email, "origin")
+		assert.ErrorIs(t, err, buildnumber.ErrProtected)
+
+		_ = bnLocal.SetMetadata("staging", user, email, buildnumber.Metadata{Protected: true})
+		_, err = bnLocal.Rename(t.Context(), "staging", "preprod", user, email, "")
+		assert.ErrorIs(t, err, buildnumber.ErrProtected)
+
+		_, err = bnLocal.Get("staging", "", "", false)
+		assert.NoError(t, err)
+		_, err = bnRemote.Get("staging", "", "", false)
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,97 @@
+package buildnumber
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/anselstetter/git-build-number/internal/repository"
+)
+
+const configSection = "build-number"
+
+type Seed struct {
+	From  string
+	Start int64
+}
+
+func (s Seed) Empty() bool {
+	return s.From == "" && s.Start == 0
+}
+
+func (bn *BuildNumber) Create(namespace string, user string, email string, seed Seed) (*Entry, error) {
+	_, err := bn.entry(bn.ref(namespace))
+	if err == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNamespaceExists, namespace)
+	} else if !errors.Is(err, ErrBuildNumberNotFound) {
+		return nil, err
+	}
+	entry, seed, err := bn.seeded(namespace, seed)
+	if err != nil {
+		return nil, err
+	}
+	if seed.From == "" {
+		return bn.Set(namespace, user, email, entry.Number)
+	}
+	msg := fmt.Sprintf("Set build number to %d for %s from %s\n", entry.Number, entry.Hash, seed.From)
+	err = bn.commit(namespace, *entry, msg, repository.Author{Name: user, Email: email}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (bn *BuildNumber) seeded(namespace string, seed Seed) (*Entry, Seed, error) {
+	if seed.Empty() {
+		var err error
+		seed, err = bn.Seed(namespace)
+		if err != nil {
+			return nil, Seed{}, err
+		}
+	}
+	head, err := bn.head()
+	if err != nil {
+		return nil, Seed{}, err
+	}
+	if seed.From == "" {
+		return &Entry{Number: max(seed.Start, 1), Hash: head.Hash}, seed, nil
+	}
+	from, err := bn.Get(seed.From, "", "", false)
+	if err != nil {
+		return nil, Seed{}, err
+	}
+	return &Entry{Number: from.Number + 1, Hash: head.Hash}, seed, nil
+}
+
+func (bn *BuildNumber) Seed(namespace string) (Seed, error) {
+	options, err := bn.repository.Config(configSection)
+	if err != nil {
+		return Seed{}, err
+	}
+	defaults := Seed{}
+	matched := Seed{}
+	for _, option := range options {
+		seed := &defaults
+		if option.Subsection != "" {
+			if !Glob(option.Subsection, namespace) {
+				continue
+			}
+			seed = &matched
+		}
+		switch strings.ToLower(option.Key) {
+		case "start":
+			start, err := strconv.ParseInt(option.Value, 10, 64)
+			if err != nil || start < 1 {
+				return Seed{}, fmt.Errorf("%w: %s.start = %s", ErrInvalidBuildNumber, configSection, option.Value)
+			}
+			seed.Start = start
+		case "from":
+			seed.From = option.Value
+		}
+	}
+	if !matched.Empty() {
+		return matched, nil
+	}
+	return defaults, nil
+}
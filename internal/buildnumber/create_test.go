@@ -0,0 +1,122 @@
+package buildnumber_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/anselstetter/git-build-number/internal/buildnumber"
+	"github.com/anselstetter/git-build-number/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func withConfig(t *testing.T, config string) repository.Repository {
+	t.Helper()
+
+	repo, path, _ := repository.NewGitTempBareRepository(true)
+	t.Cleanup(func() {
+		_ = os.RemoveAll(*path)
+	})
+	file, _ := os.OpenFile(filepath.Join(*path, "config"), os.O_APPEND|os.O_WRONLY, 0o644)
+	_, _ = file.WriteString(config)
+	_ = file.Close()
+	return repo
+}
+
+func TestCreate(t *testing.T) {
+	t.Run("without seed", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		entry, err := bn.Create("test", user, email, buildnumber.Seed{})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), entry.Number)
+	})
+	t.Run("with start", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		entry, err := bn.Create("test", user, email, buildnumber.Seed{Start: 10000})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(10000), entry.Number)
+	})
+	t.Run("with from", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+		_, _ = bn.Set("prod", user, email, 41)
+
+		entry, err := bn.Create("release/1", user, email, buildnumber.Seed{From: "prod"})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(42), entry.Number)
+
+		commits, _ := repo.Commits("refs/build-number/release/1")
+		assert.Equal(t, "Set build number to 42 for "+entry.Hash+" from prod\n", commits[0].Message)
+	})
+	t.Run("with missing from", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		_, err := bn.Create("release/1", user, email, buildnumber.Seed{From: "prod"})
+		assert.ErrorIs(t, err, buildnumber.ErrBuildNumberNotFound)
+	})
+	t.Run("existing namespace", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+		_, _ = bn.Set("test", user, email, 1)
+
+		_, err := bn.Create("test", user, email, buildnumber.Seed{Start: 5})
+		assert.ErrorIs(t, err, buildnumber.ErrNamespaceExists)
+	})
+	t.Run("with config", func(t *testing.T) {
+		t.Parallel()
+
+		repo := withConfig(t, "[build-number]\n\tstart = 100\n[build-number \"release/*\"]\n\tfrom = prod\n")
+		bn := buildnumber.New(repo)
+
+		entry, err := bn.Get("prod", user, email, true)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(100), entry.Number)
+
+		entry, _, err = bn.Inc("release/1", user, email, false)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(101), entry.Number)
+	})
+	t.Run("next with config", func(t *testing.T) {
+		t.Parallel()
+
+		repo := withConfig(t, "[build-number]\n\tstart = 100\n[build-number \"release/*\"]\n\tfrom = prod\n")
+		bn := buildnumber.New(repo)
+
+		entry, _, err := bn.Next("prod", false)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(100), entry.Number)
+
+		_, _ = bn.Set("prod", user, email, 41)
+		entry, _, err = bn.Next("release/1", false)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(42), entry.Number)
+
+		inc, _, err := bn.Inc("release/1", user, email, false)
+		assert.NoError(t, err)
+		assert.Equal(t, inc, entry)
+	})
+	t.Run("with invalid config", func(t *testing.T) {
+		t.Parallel()
+
+		repo := withConfig(t, "[build-number]\n\tstart = first\n")
+		bn := buildnumber.New(repo)
+
+		_, err := bn.Get("test", user, email, true)
+		assert.ErrorIs(t, err, buildnumber.ErrInvalidBuildNumber)
+	})
+}
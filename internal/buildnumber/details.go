@@ -0,0 +1,116 @@
+package buildnumber
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/anselstetter/git-build-number/internal/repository"
+)
+
+type Details struct {
+	Name     string    `json:"name"`
+	Target   string    `json:"target,omitempty"`
+	Number   int64     `json:"number"`
+	Hash     string    `json:"hash"`
+	Subject  string    `json:"subject"`
+	Updated  time.Time `json:"updated"`
+	Author   string    `json:"author"`
+	Builds   *int      `json:"builds"`
+	State    State     `json:"state,omitempty"`
+	Metadata Metadata  `json:"metadata"`
+}
+
+func (bn *BuildNumber) Details(ctx context.Context, filter Filter, remoteName string) ([]Details, error) {
+	refs, err := bn.repository.Refs(repository.WithPrefix(bn.refName+"/"), repository.WithSymbolic())
+	if err != nil {
+		return nil, err
+	}
+	states := map[string]State{}
+	if remoteName != "" {
+		statuses, err := bn.Status(ctx, remoteName, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, status := range statuses {
+			states[status.Name] = status.State
+		}
+	}
+	details := []Details{}
+	for _, ref := range refs {
+		name := bn.namespace(ref)
+		if !filter.Match(name) {
+			continue
+		}
+		target := strings.TrimPrefix(ref.Target, bn.refName+"/")
+		if ref.Hash == "" {
+			details = append(details, Details{Name: name, Target: target})
+			continue
+		}
+		d, err := bn.details(ref)
+		if err != nil {
+			return nil, err
+		}
+		d.Name = name
+		d.Target = target
+		d.State = states[name]
+		if target != "" {
+			d.State = states[target]
+		}
+		details = append(details, *d)
+	}
+	return details, nil
+}
+
+func (bn *BuildNumber) details(ref repository.Ref) (*Details, error) {
+	tip, err := bn.repository.Lookup(ref.Hash)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := bn.entry(ref.Path)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := bn.metadata(ref.Path)
+	if err != nil {
+		return nil, err
+	}
+	builds, err := bn.builds(ref.Path)
+	if err != nil {
+		return nil, err
+	}
+	d := Details{
+		Number:   entry.Number,
+		Hash:     entry.Hash,
+		Updated:  tip.When,
+		Author:   tip.Author.Name,
+		Builds:   builds,
+		Metadata: *metadata,
+	}
+	commit, err := bn.repository.Lookup(entry.Hash)
+	if err != nil && !errors.Is(err, repository.ErrObjectNotFound) {
+		return nil, err
+	}
+	if commit != nil {
+		d.Subject, _, _ = strings.Cut(commit.Message, "\n")
+	}
+	return &d, nil
+}
+
+func (bn *BuildNumber) builds(refName string) (*int, error) {
+	commits, err := bn.repository.Commits(refName)
+	if err != nil && errors.Is(err, repository.ErrShallowHistory) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	numbers := map[string]bool{}
+	for _, commit := range commits {
+		if len(commit.Headers) > 0 {
+			numbers[commit.Headers[0].Key] = true
+		}
+	}
+	builds := len(numbers)
+	return &builds, nil
+}
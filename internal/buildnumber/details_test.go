@@ -0,0 +1,42 @@
+package buildnumber_test
+
+import (
+	"testing"
+
+	"github.com/anselstetter/git-build-number/internal/buildnumber"
+	"github.com/anselstetter/git-build-number/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetails(t *testing.T) {
+	repo, head, _ := repository.NewGitInMemoryRepository(true)
+	remote := addRemote(t, "origin", true, repo)
+	bn := buildnumber.New(repo)
+	bnRemote := buildnumber.New(remote)
+
+	_, _ = bn.Set("prod", "First Last", email, 1)
+	_, _ = bn.Set("prod", "First Last", email, 2)
+	_ = bn.SetMetadata("prod", user, email, buildnumber.Metadata{Owner: "team"})
+	_ = bn.Push(t.Context(), "origin", buildnumber.Filter{})
+	_, _ = bn.Set("dev", user, email, 5)
+	_ = bn.Alias("current", "prod")
+	_, _ = bnRemote.Set("remote", user, email, 1)
+
+	details, err := bn.Details(t.Context(), buildnumber.Filter{Exclude: []string{"dev"}}, "origin")
+	assert.NoError(t, err)
+	assert.Len(t, details, 2)
+
+	for _, d := range details {
+		assert.Equal(t, int64(2), d.Number)
+		assert.Equal(t, head.Hash, d.Hash)
+		assert.Equal(t, "Initial commit", d.Subject)
+		assert.Equal(t, user, d.Author)
+		assert.Equal(t, 2, *d.Builds)
+		assert.Equal(t, buildnumber.StateInSync, d.State)
+		assert.Equal(t, buildnumber.Metadata{Owner: "team"}, d.Metadata)
+	}
+	assert.Equal(t, "current", details[0].Name)
+	assert.Equal(t, "prod", details[0].Target)
+	assert.Equal(t, "prod", details[1].Name)
+	assert.Equal(t, "", details[1].Target)
+}
@@ -0,0 +1,56 @@
+package buildnumber
+
+import (
+	"regexp"
+	"strings"
+)
+
+type Filter struct {
+	Include []string
+	Exclude []string
+}
+
+func (f Filter) Empty() bool {
+	return len(f.Include) == 0 && len(f.Exclude) == 0
+}
+
+func (f Filter) Match(namespace string) bool {
+	for _, pattern := range f.Exclude {
+		if Glob(pattern, namespace) {
+			return false
+		}
+	}
+	if len(f.Include) == 0 {
+		return true
+	}
+	for _, pattern := range f.Include {
+		if Glob(pattern, namespace) {
+			return true
+		}
+	}
+	return false
+}
+
+func Glob(pattern string, namespace string) bool {
+	var expr strings.Builder
+
+	expr.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				expr.WriteString(".*")
+				i++
+			} else {
+				expr.WriteString("[^/]*")
+			}
+		case '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	expr.WriteString("$")
+
+	return regexp.MustCompile(expr.String()).MatchString(namespace)
+}
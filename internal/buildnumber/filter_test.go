@@ -0,0 +1,59 @@
+package buildnumber_test
+
+import (
+	"testing"
+
+	"github.com/anselstetter/git-build-number/internal/buildnumber"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGlob(t *testing.T) {
+	tests := []struct {
+		pattern   string
+		namespace string
+		expected  bool
+	}{
+		{pattern: "prod", namespace: "prod", expected: true},
+		{pattern: "prod", namespace: "production", expected: false},
+		{pattern: "pr/*", namespace: "pr/123", expected: true},
+		{pattern: "pr/*", namespace: "pr/123/retry", expected: false},
+		{pattern: "release/**", namespace: "release/2026/10", expected: true},
+		{pattern: "release/**", namespace: "releases", expected: false},
+		{pattern: "v?", namespace: "v1", expected: true},
+		{pattern: "a.b", namespace: "axb", expected: false},
+	}
+	for _, test := range tests {
+		t.Run(test.pattern+" "+test.namespace, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, buildnumber.Glob(test.pattern, test.namespace))
+		})
+	}
+}
+
+func TestFilter(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		filter := buildnumber.Filter{}
+		assert.True(t, filter.Empty())
+		assert.True(t, filter.Match("anything"))
+	})
+	t.Run("include and exclude", func(t *testing.T) {
+		t.Parallel()
+
+		filter := buildnumber.Filter{Include: []string{"pr/*", "prod"}, Exclude: []string{"pr/old"}}
+		assert.False(t, filter.Empty())
+		assert.True(t, filter.Match("pr/1"))
+		assert.True(t, filter.Match("prod"))
+		assert.False(t, filter.Match("pr/old"))
+		assert.False(t, filter.Match("dev"))
+	})
+	t.Run("exclude only", func(t *testing.T) {
+		t.Parallel()
+
+		filter := buildnumber.Filter{Exclude: []string{"pr/**"}}
+		assert.True(t, filter.Match("prod"))
+		assert.False(t, filter.Match("pr/1/2"))
+	})
+}
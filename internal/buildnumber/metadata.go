@@ -0,0 +1,111 @@
+package buildnumber
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/anselstetter/git-build-number/internal/repository"
+)
+
+var (
+	ErrFrozen    = errors.New("namespace is frozen")
+	ErrProtected = errors.New("namespace is protected")
+)
+
+type Metadata struct {
+	Description string `json:"description,omitempty"`
+	Owner       string `json:"owner,omitempty"`
+	Protected   bool   `json:"protected,omitempty"`
+	Frozen      bool   `json:"frozen,omitempty"`
+}
+
+func (bn *BuildNumber) Metadata(namespace string) (*Metadata, error) {
+	return bn.metadata(bn.ref(namespace))
+}
+
+func (bn *BuildNumber) RemoteMetadata(remoteName string, namespace string) (*Metadata, error) {
+	return bn.metadata(bn.trackingRef(remoteName, namespace))
+}
+
+func (bn *BuildNumber) SetMetadata(namespace string, user string, email string, metadata Metadata) error {
+	entry, err := bn.Get(namespace, "", "", false)
+	if err != nil {
+		return err
+	}
+	content, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return err
+	}
+	msg := fmt.Sprintf("Update metadata for %s\n", namespace)
+	headers := []repository.Header{{Key: strconv.FormatInt(entry.Number, 10), Value: entry.Hash}}
+
+	_, err = bn.repository.Commit(bn.ref(namespace), bn.metadataFileName, append(content, '\n'), msg,
+		repository.WithAuthor(repository.Author{Name: user, Email: email}),
+		repository.WithHeaders(headers),
+	)
+	return err
+}
+
+func (bn *BuildNumber) Protected(namespaces ...string) ([]string, error) {
+	protected := []string{}
+	for _, namespace := range namespaces {
+		metadata, err := bn.Metadata(namespace)
+		if err != nil {
+			return nil, err
+		}
+		if metadata.Protected {
+			protected = append(protected, namespace)
+		}
+	}
+	return protected, nil
+}
+
+func (bn *BuildNumber) ProtectedRemote(ctx context.Context, remoteName string, filter Filter, states ...State) ([]string, error) {
+	statuses, err := bn.Status(ctx, remoteName, filter)
+	if err != nil {
+		return nil, err
+	}
+	protected := []string{}
+	for _, status := range statuses {
+		if status.Remote == nil || (len(states) > 0 && !slices.Contains(states, status.State)) {
+			continue
+		}
+		metadata, err := bn.RemoteMetadata(remoteName, status.Name)
+		if err != nil {
+			return nil, err
+		}
+		if metadata.Protected {
+			protected = append(protected, status.Name)
+		}
+	}
+	return protected, nil
+}
+
+func (bn *BuildNumber) metadata(refName string) (*Metadata, error) {
+	content, err := bn.repository.Content(refName, bn.metadataFileName)
+	if err != nil && (errors.Is(err, repository.ErrFileNotFound) || errors.Is(err, repository.ErrReferenceNotFound)) {
+		return &Metadata{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	metadata := Metadata{}
+	if err := json.Unmarshal(*content, &metadata); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFormat, err)
+	}
+	return &metadata, nil
+}
+
+func (bn *BuildNumber) frozen(namespace string) error {
+	metadata, err := bn.Metadata(namespace)
+	if err != nil {
+		return err
+	}
+	if metadata.Frozen {
+		return fmt.Errorf("%w: %s", ErrFrozen, namespace)
+	}
+	return nil
+}
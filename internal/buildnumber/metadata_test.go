@@ -0,0 +1,115 @@
+package buildnumber_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anselstetter/git-build-number/internal/buildnumber"
+	"github.com/anselstetter/git-build-number/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetadata(t *testing.T) {
+	t.Run("without metadata", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+		_, _ = bn.Set("test", user, email, 1)
+
+		metadata, err := bn.Metadata("test")
+		assert.NoError(t, err)
+		assert.Equal(t, &buildnumber.Metadata{}, metadata)
+	})
+	t.Run("missing namespace", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		err := bn.SetMetadata("test", user, email, buildnumber.Metadata{Owner: "team"})
+		assert.ErrorIs(t, err, buildnumber.ErrBuildNumberNotFound)
+	})
+	t.Run("with metadata", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+		_, _ = bn.Set("test", user, email, 1)
+
+		expected := buildnumber.Metadata{Description: "release line", Owner: "team", Protected: true}
+		err := bn.SetMetadata("test", user, email, expected)
+		assert.NoError(t, err)
+
+		entry, _, err := bn.Inc("test", user, email, true)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), entry.Number)
+
+		metadata, err := bn.Metadata("test")
+		assert.NoError(t, err)
+		assert.Equal(t, &expected, metadata)
+
+		hash, err := bn.Hash("test", 1)
+		assert.NoError(t, err)
+		assert.Equal(t, entry.Hash, hash.Hash)
+	})
+	t.Run("frozen", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+		_, _ = bn.Set("test", user, email, 1)
+		_ = bn.SetMetadata("test", user, email, buildnumber.Metadata{Frozen: true})
+
+		_, _, err := bn.Inc("test", user, email, true)
+		assert.ErrorIs(t, err, buildnumber.ErrFrozen)
+
+		_, err = bn.Set("test", user, email, 5)
+		assert.ErrorIs(t, err, buildnumber.ErrFrozen)
+
+		entry, _ := bn.Get("test", user, email, false)
+		assert.Equal(t, int64(1), entry.Number)
+
+		_ = bn.SetMetadata("test", user, email, buildnumber.Metadata{})
+
+		entry, err = bn.Set("test", user, email, 5)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), entry.Number)
+	})
+}
+
+func TestProtected(t *testing.T) {
+	repo, _, _ := repository.NewGitInMemoryRepository(true)
+	bn := buildnumber.New(repo)
+	_, _ = bn.Set("prod", user, email, 1)
+	_, _ = bn.Set("pr/1", user, email, 1)
+	_ = bn.SetMetadata("prod", user, email, buildnumber.Metadata{Protected: true})
+
+	protected, err := bn.Protected("prod", "pr/1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"prod"}, protected)
+
+	stale, err := bn.Stale(t.Context(), buildnumber.PruneOptions{Filter: buildnumber.Filter{Include: []string{"**"}}}, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"pr/1"}, names(stale))
+}
+
+func TestProtectedRemote(t *testing.T) {
+	repo, _, _ := repository.NewGitInMemoryRepository(true)
+	remote := addRemote(t, "origin", true, repo)
+
+	bnLocal := buildnumber.New(repo)
+	bnRemote := buildnumber.New(remote)
+
+	_, _ = bnRemote.Set("prod", user, email, 1)
+	_, _ = bnRemote.Set("dev", user, email, 1)
+	_ = bnRemote.SetMetadata("prod", user, email, buildnumber.Metadata{Protected: true})
+
+	protected, err := bnLocal.ProtectedRemote(t.Context(), "origin", buildnumber.Filter{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"prod"}, protected)
+
+	protected, err = bnLocal.ProtectedRemote(t.Context(), "origin", buildnumber.Filter{}, buildnumber.StateInSync)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{}, protected)
+}
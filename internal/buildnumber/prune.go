@@ -0,0 +1,152 @@
+package buildnumber
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anselstetter/git-build-number/internal/repository"
+)
+
+var (
+	ErrInvalidAge      = errors.New("age is invalid")
+	ErrMissingCriteria = errors.New("please provide at least one criteria")
+)
+
+type PruneOptions struct {
+	Filter    Filter
+	OlderThan time.Duration
+	Gone      bool
+	Remote    string
+	Max       int
+}
+
+func (o PruneOptions) Empty() bool {
+	return o.Filter.Empty() && o.OlderThan == 0 && !o.Gone && o.Max == 0
+}
+
+type Stale struct {
+	Name  string
+	Entry Entry
+	When  time.Time
+}
+
+func (bn *BuildNumber) Stale(ctx context.Context, options PruneOptions, now time.Time) ([]Stale, error) {
+	if options.Empty() {
+		return nil, ErrMissingCriteria
+	}
+	refs, err := bn.repository.Refs(repository.WithPrefix(bn.refName + "/"))
+	if err != nil {
+		return nil, err
+	}
+	branches, err := bn.branches(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	candidates := []Stale{}
+	for _, ref := range refs {
+		name := bn.namespace(ref)
+		if !options.Filter.Match(name) {
+			continue
+		}
+		commit, err := bn.lastBuild(ref.Hash)
+		if err != nil {
+			return nil, err
+		}
+		entry, err := commitEntry(*commit)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, Stale{Name: name, Entry: *entry, When: commit.When})
+	}
+	slices.SortStableFunc(candidates, func(a, b Stale) int {
+		return b.When.Compare(a.When)
+	})
+	stale := []Stale{}
+	for i, candidate := range candidates {
+		if options.Max > 0 && i < options.Max {
+			continue
+		}
+		if options.OlderThan > 0 && now.Sub(candidate.When) < options.OlderThan {
+			continue
+		}
+		if options.Gone && branches[candidate.Name] {
+			continue
+		}
+		metadata, err := bn.Metadata(candidate.Name)
+		if err != nil {
+			return nil, err
+		}
+		if metadata.Protected {
+			continue
+		}
+		stale = append(stale, candidate)
+	}
+	slices.SortFunc(stale, func(a, b Stale) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return stale, nil
+}
+
+func (bn *BuildNumber) Prune(ctx context.Context, remoteName string, namespaces ...string) error {
+	if err := bn.Delete(namespaces...); err != nil {
+		return err
+	}
+	if remoteName == "" {
+		return nil
+	}
+	for _, namespace := range namespaces {
+		err := bn.DeleteRemote(ctx, remoteName, namespace)
+		if err != nil && !errors.Is(err, repository.ErrReferenceNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (bn *BuildNumber) branches(ctx context.Context, options PruneOptions) (map[string]bool, error) {
+	branches := map[string]bool{}
+	if !options.Gone {
+		return branches, nil
+	}
+	var (
+		refs []repository.Ref
+		err  error
+	)
+	if options.Remote != "" {
+		refs, err = bn.repository.RemoteRefs(ctx, options.Remote, repository.WithPrefix("refs/heads/"))
+	} else {
+		refs, err = bn.repository.Refs(repository.WithPrefix("refs/heads/"))
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, ref := range refs {
+		branches[strings.TrimPrefix(ref.Path, "refs/heads/")] = true
+	}
+	return branches, nil
+}
+
+func ParseAge(s string) (time.Duration, error) {
+	units := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+	for suffix, unit := range units {
+		value, ok := strings.CutSuffix(s, suffix)
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("%w: %s", ErrInvalidAge, s)
+		}
+		return time.Duration(n) * unit, nil
+	}
+	age, err := time.ParseDuration(s)
+	if err != nil || age < 0 {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidAge, s)
+	}
+	return age, nil
+}
@@ -0,0 +1,159 @@
+package buildnumber_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/anselstetter/git-build-number/internal/buildnumber"
+	"github.com/anselstetter/git-build-number/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func names(stale []buildnumber.Stale) []string {
+	names := []string{}
+	for _, s := range stale {
+		names = append(names, s.Name)
+	}
+	return names
+}
+
+func TestStale(t *testing.T) {
+	day := int64(24 * 60 * 60)
+	now := time.Unix(100*day, 0)
+
+	repo, _, _ := repository.NewGitInMemoryRepository(true)
+	bn := buildnumber.New(repo)
+
+	for _, ns := range []struct {
+		name string
+		age  int64
+	}{
+		{"pr/1", 60},
+		{"pr/2", 40},
+		{"pr/3", 10},
+		{"feature", 50},
+		{"main", 1},
+	} {
+		t.Setenv("SOURCE_DATE_EPOCH", strconv.FormatInt(now.Unix()-ns.age*day, 10))
+		_, _ = bn.Set(ns.name, user, email, 1)
+	}
+	_, _ = repo.Commit("refs/heads/feature", "file", []byte("content"), "commit")
+
+	tests := []struct {
+		name     string
+		options  buildnumber.PruneOptions
+		expected []string
+		err      error
+	}{
+		{
+			name: "without criteria",
+			err:  buildnumber.ErrMissingCriteria,
+		},
+		{
+			name:     "glob",
+			options:  buildnumber.PruneOptions{Filter: buildnumber.Filter{Include: []string{"pr/*"}, Exclude: []string{"pr/3"}}},
+			expected: []string{"pr/1", "pr/2"},
+		},
+		{
+			name:     "older than",
+			options:  buildnumber.PruneOptions{OlderThan: 45 * 24 * time.Hour},
+			expected: []string{"feature", "pr/1"},
+		},
+		{
+			name:     "gone",
+			options:  buildnumber.PruneOptions{Gone: true, Filter: buildnumber.Filter{Exclude: []string{"main"}}},
+			expected: []string{"pr/1", "pr/2", "pr/3"},
+		},
+		{
+			name:     "max",
+			options:  buildnumber.PruneOptions{Max: 2, Filter: buildnumber.Filter{Include: []string{"pr/*"}}},
+			expected: []string{"pr/1"},
+		},
+		{
+			name:     "combined",
+			options:  buildnumber.PruneOptions{Gone: true, OlderThan: 30 * 24 * time.Hour},
+			expected: []string{"pr/1", "pr/2"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stale, err := bn.Stale(t.Context(), test.options, now)
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, names(stale))
+		})
+	}
+}
+
+func TestStaleMetadata(t *testing.T) {
+	day := int64(24 * 60 * 60)
+	now := time.Unix(100*day, 0)
+
+	repo, _, _ := repository.NewGitInMemoryRepository(true)
+	bn := buildnumber.New(repo)
+
+	t.Setenv("SOURCE_DATE_EPOCH", strconv.FormatInt(now.Unix()-60*day, 10))
+	_, _ = bn.Set("pr/1", user, email, 1)
+	_, _ = bn.Set("pr/1", user, email, 2)
+	t.Setenv("SOURCE_DATE_EPOCH", strconv.FormatInt(now.Unix()-day, 10))
+	_ = bn.SetMetadata("pr/1", user, email, buildnumber.Metadata{Description: "updated"})
+
+	stale, err := bn.Stale(t.Context(), buildnumber.PruneOptions{OlderThan: 30 * 24 * time.Hour}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"pr/1"}, names(stale))
+	assert.Equal(t, int64(2), stale[0].Entry.Number)
+	assert.Equal(t, now.Unix()-60*day, stale[0].When.Unix())
+}
+
+func TestPrune(t *testing.T) {
+	repo, _, _ := repository.NewGitInMemoryRepository(true)
+	remote := addRemote(t, "origin", true, repo)
+
+	bnLocal := buildnumber.New(repo)
+	bnRemote := buildnumber.New(remote)
+
+	_, _ = bnLocal.Set("pr/1", user, email, 1)
+	_, _ = bnLocal.Set("pr/2", user, email, 2)
+	entry, _ := bnLocal.Set("main", user, email, 3)
+	_, _ = bnRemote.Set("pr/1", user, email, 1)
+
+	err := bnLocal.Prune(t.Context(), "origin", "pr/1", "pr/2")
+	assert.NoError(t, err)
+
+	ns, _ := bnLocal.Namespaces()
+	assert.Equal(t, []buildnumber.Namespace{{Name: "main", Entry: *entry}}, ns)
+
+	ns, _ = bnRemote.Namespaces()
+	assert.Equal(t, []buildnumber.Namespace{}, ns)
+}
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		age      string
+		expected time.Duration
+		err      error
+	}{
+		{age: "30d", expected: 30 * 24 * time.Hour},
+		{age: "2w", expected: 14 * 24 * time.Hour},
+		{age: "12h", expected: 12 * time.Hour},
+		{age: "-1d", err: buildnumber.ErrInvalidAge},
+		{age: "invalid", err: buildnumber.ErrInvalidAge},
+	}
+	for _, test := range tests {
+		t.Run(test.age, func(t *testing.T) {
+			t.Parallel()
+
+			age, err := buildnumber.ParseAge(test.age)
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, age)
+		})
+	}
+}
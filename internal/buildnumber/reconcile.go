@@ -0,0 +1,245 @@
+package buildnumber
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/anselstetter/git-build-number/internal/repository"
+)
+
+var (
+	ErrUnknownStrategy = errors.New("unknown strategy")
+)
+
+type Strategy string
+
+const (
+	StrategyRemote    Strategy = "remote"
+	StrategyHighest   Strategy = "highest"
+	StrategyTimestamp Strategy = "timestamp"
+)
+
+var Strategies = []Strategy{StrategyRemote, StrategyHighest, StrategyTimestamp}
+
+func ParseStrategy(s string) (Strategy, error) {
+	for _, strategy := range Strategies {
+		if string(strategy) == s {
+			return strategy, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrUnknownStrategy, s)
+}
+
+type Side string
+
+const (
+	SideLocal  Side = "local"
+	SideRemote Side = "remote"
+)
+
+type Renumbered struct {
+	Side  Side
+	Old   int64
+	New   int64
+	Entry Entry
+}
+
+type Duplicate struct {
+	Number int64
+	Local  string
+	Remote string
+}
+
+type Reconciliation struct {
+	State      State
+	Entry      *Entry
+	Renumbered []Renumbered
+	Duplicates []Duplicate
+}
+
+type historyEntry struct {
+	side     Side
+	original int64
+	entry    Entry
+	author   repository.Author
+	when     time.Time
+}
+
+func (bn *BuildNumber) Reconcile(ctx context.Context, namespace string, remoteName string, strategy Strategy) (*Reconciliation, error) {
+	trackingRef := bn.trackingRef(remoteName, namespace)
+
+	err := bn.repository.Fetch(ctx, bn.ref(namespace), remoteName, true, repository.WithDestination(trackingRef))
+	if err != nil && errors.Is(err, repository.ErrReferenceNotFound) {
+		return nil, errors.Join(err, ErrBuildNumberNotFound)
+	} else if err != nil {
+		return nil, err
+	}
+	remoteCommits, err := bn.repository.Commits(trackingRef)
+	if err != nil {
+		return nil, err
+	}
+	localCommits, err := bn.repository.Commits(bn.ref(namespace))
+	if err != nil && errors.Is(err, repository.ErrReferenceNotFound) {
+		return nil, errors.Join(err, ErrBuildNumberNotFound)
+	} else if err != nil {
+		return nil, err
+	}
+	remoteTip, localTip := remoteCommits[0].Hash, localCommits[0].Hash
+
+	base := -1
+	for i, commit := range localCommits {
+		if slices.ContainsFunc(remoteCommits, func(c repository.Commit) bool { return c.Hash == commit.Hash }) {
+			base = i
+			break
+		}
+	}
+	localOnly := localCommits
+	remoteOnly := remoteCommits
+	baseHash := ""
+	baseNumber := int64(0)
+	if base >= 0 {
+		entry, err := commitEntry(localCommits[base])
+		if err != nil {
+			return nil, err
+		}
+		baseHash = localCommits[base].Hash
+		baseNumber = entry.Number
+		localOnly = localCommits[:base]
+		remoteOnly = remoteCommits[:slices.IndexFunc(remoteCommits, func(c repository.Commit) bool { return c.Hash == baseHash })]
+	}
+	result := Reconciliation{Renumbered: []Renumbered{}, Duplicates: []Duplicate{}}
+
+	switch {
+	case localTip == remoteTip:
+		result.State = StateInSync
+	case len(remoteOnly) == 0:
+		result.State = StateAhead
+	case len(localOnly) == 0:
+		result.State = StateBehind
+		if err := bn.repository.Update(bn.ref(namespace), remoteTip, localTip); err != nil {
+			return nil, err
+		}
+	default:
+		result.State = StateDiverged
+	}
+	if result.State != StateDiverged {
+		entry, err := bn.Get(namespace, "", "", false)
+		if err != nil {
+			return nil, err
+		}
+		result.Entry = entry
+		return &result, nil
+	}
+	local, err := historyEntries(SideLocal, localOnly)
+	if err != nil {
+		return nil, err
+	}
+	remote, err := historyEntries(SideRemote, remoteOnly)
+	if err != nil {
+		return nil, err
+	}
+	result.Duplicates = duplicates(local, remote)
+
+	start, entries, err := plan(strategy, baseHash, baseNumber, localTip, remoteTip, local, remote)
+	if err != nil {
+		return nil, err
+	}
+	if start != "" {
+		err = bn.repository.Update(bn.ref(namespace), start, localTip)
+	} else {
+		err = bn.repository.Delete(bn.ref(namespace))
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		msg := fmt.Sprintf("Set build number to %d for %s\n", e.entry.Number, e.entry.Hash)
+		if err := bn.commit(namespace, e.entry, msg, e.author, &e.when); err != nil {
+			return nil, err
+		}
+	}
+	for _, e := range entries {
+		if e.original != e.entry.Number {
+			result.Renumbered = append(result.Renumbered, Renumbered{Side: e.side, Old: e.original, New: e.entry.Number, Entry: e.entry})
+		}
+	}
+	entry, err := bn.Get(namespace, "", "", false)
+	if err != nil {
+		return nil, err
+	}
+	result.Entry = entry
+
+	return &result, nil
+}
+
+func plan(strategy Strategy, baseHash string, baseNumber int64, localTip string, remoteTip string, local []historyEntry, remote []historyEntry) (string, []historyEntry, error) {
+	switch strategy {
+	case StrategyRemote:
+		return remoteTip, renumber(local, last(remote).entry.Number+1), nil
+	case StrategyHighest:
+		if last(local).entry.Number > last(remote).entry.Number {
+			return localTip, renumber(remote, last(local).entry.Number+1), nil
+		}
+		return remoteTip, renumber(local, last(remote).entry.Number+1), nil
+	case StrategyTimestamp:
+		merged := slices.Concat(remote, local)
+		slices.SortStableFunc(merged, func(a, b historyEntry) int {
+			return a.when.Compare(b.when)
+		})
+		return baseHash, renumber(merged, baseNumber+1), nil
+	default:
+		return "", nil, fmt.Errorf("%w: %s", ErrUnknownStrategy, strategy)
+	}
+}
+
+func historyEntries(side Side, commits []repository.Commit) ([]historyEntry, error) {
+	entries := make([]historyEntry, 0, len(commits))
+	for _, commit := range slices.Backward(commits) {
+		entry, err := commitEntry(commit)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, historyEntry{side: side, original: entry.Number, entry: *entry, author: commit.Author, when: commit.When})
+	}
+	return entries, nil
+}
+
+func commitEntry(commit repository.Commit) (*Entry, error) {
+	if len(commit.Headers) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidFormat, commit.Hash)
+	}
+	number, err := strconv.ParseInt(commit.Headers[0].Key, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBuildNumber, err)
+	}
+	return &Entry{Number: number, Hash: commit.Headers[0].Value}, nil
+}
+
+func duplicates(local []historyEntry, remote []historyEntry) []Duplicate {
+	duplicates := []Duplicate{}
+	for _, l := range local {
+		for _, r := range remote {
+			if l.entry.Number == r.entry.Number {
+				duplicates = append(duplicates, Duplicate{Number: l.entry.Number, Local: l.entry.Hash, Remote: r.entry.Hash})
+			}
+		}
+	}
+	return duplicates
+}
+
+func renumber(entries []historyEntry, start int64) []historyEntry {
+	renumbered := make([]historyEntry, 0, len(entries))
+	for i, e := range entries {
+		e.entry.Number = start + int64(i)
+		renumbered = append(renumbered, e)
+	}
+	return renumbered
+}
+
+func last(entries []historyEntry) historyEntry {
+	return entries[len(entries)-1]
+}
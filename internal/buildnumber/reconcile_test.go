@@ -0,0 +1,137 @@
+package buildnumber_test
+
+import (
+	"testing"
+
+	"github.com/anselstetter/git-build-number/internal/buildnumber"
+	"github.com/anselstetter/git-build-number/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type step struct {
+	epoch  string
+	local  bool
+	number int64
+}
+
+func diverge(t *testing.T, steps ...step) (repository.Repository, *repository.Ref, *repository.Ref) {
+	t.Helper()
+
+	repo, _, _ := repository.NewGitInMemoryRepository(true)
+	remote := addRemote(t, "origin", true, repo)
+
+	bnLocal := buildnumber.New(repo)
+	bnRemote := buildnumber.New(remote)
+
+	t.Setenv("SOURCE_DATE_EPOCH", "100")
+	_, _ = bnRemote.Set("test", user, email, 1)
+	_ = bnLocal.Fetch(t.Context(), "origin", buildnumber.Filter{}, 0)
+
+	localHead, _ := repo.Commit("refs/heads/main", "local", []byte("local"), "Local commit", repository.WithHead())
+	remoteHead, _ := remote.Head()
+
+	for _, step := range steps {
+		t.Setenv("SOURCE_DATE_EPOCH", step.epoch)
+		if step.local {
+			_, _ = bnLocal.Set("test", user, email, step.number)
+		} else {
+			_, _ = bnRemote.Set("test", user, email, step.number)
+		}
+	}
+	return repo, localHead, remoteHead
+}
+
+func TestReconcile(t *testing.T) {
+	t.Run("remote", func(t *testing.T) {
+		repo, localHead, _ := diverge(t, step{"200", false, 2}, step{"300", true, 2}, step{"400", false, 3})
+		bn := buildnumber.New(repo)
+
+		result, err := bn.Reconcile(t.Context(), "test", "origin", buildnumber.StrategyRemote)
+		assert.NoError(t, err)
+
+		assert.Equal(t, buildnumber.StateDiverged, result.State)
+		assert.Equal(t, buildnumber.Entry{Number: 4, Hash: localHead.Hash}, *result.Entry)
+		assert.Len(t, result.Duplicates, 1)
+		assert.Equal(t, int64(2), result.Duplicates[0].Number)
+		assert.Equal(t, []buildnumber.Renumbered{{Side: buildnumber.SideLocal, Old: 2, New: 4, Entry: *result.Entry}}, result.Renumbered)
+
+		commits, _ := repo.Commits("refs/build-number/test")
+		assert.Len(t, commits, 4)
+
+		statuses, _ := bn.Status(t.Context(), "origin", buildnumber.Filter{})
+		assert.Equal(t, buildnumber.StateAhead, statuses[0].State)
+	})
+	t.Run("highest", func(t *testing.T) {
+		repo, _, remoteHead := diverge(t, step{"200", false, 2}, step{"300", true, 2}, step{"400", false, 3}, step{"500", true, 5})
+		bn := buildnumber.New(repo)
+
+		result, err := bn.Reconcile(t.Context(), "test", "origin", buildnumber.StrategyHighest)
+		assert.NoError(t, err)
+
+		assert.Equal(t, buildnumber.Entry{Number: 7, Hash: remoteHead.Hash}, *result.Entry)
+		assert.Len(t, result.Renumbered, 2)
+
+		entry, err := bn.Hash("test", 5)
+		assert.NoError(t, err)
+		assert.NotEqual(t, remoteHead.Hash, entry.Hash)
+	})
+	t.Run("timestamp", func(t *testing.T) {
+		repo, localHead, remoteHead := diverge(t, step{"200", false, 2}, step{"300", true, 2}, step{"400", false, 3})
+		bn := buildnumber.New(repo)
+
+		result, err := bn.Reconcile(t.Context(), "test", "origin", buildnumber.StrategyTimestamp)
+		assert.NoError(t, err)
+
+		assert.Equal(t, buildnumber.Entry{Number: 4, Hash: remoteHead.Hash}, *result.Entry)
+
+		entry, err := bn.Hash("test", 3)
+		assert.NoError(t, err)
+		assert.Equal(t, localHead.Hash, entry.Hash)
+
+		commits, _ := repo.Commits("refs/build-number/test")
+		assert.Len(t, commits, 4)
+		assert.Equal(t, int64(400), commits[0].When.Unix())
+		assert.Equal(t, int64(300), commits[1].When.Unix())
+		assert.Equal(t, int64(200), commits[2].When.Unix())
+	})
+	t.Run("behind", func(t *testing.T) {
+		repo, _, remoteHead := diverge(t, step{"200", false, 2})
+		bn := buildnumber.New(repo)
+
+		result, err := bn.Reconcile(t.Context(), "test", "origin", buildnumber.StrategyRemote)
+		assert.NoError(t, err)
+
+		assert.Equal(t, buildnumber.StateBehind, result.State)
+		assert.Equal(t, buildnumber.Entry{Number: 2, Hash: remoteHead.Hash}, *result.Entry)
+		assert.Empty(t, result.Duplicates)
+	})
+	t.Run("ahead", func(t *testing.T) {
+		repo, localHead, _ := diverge(t, step{"200", true, 2})
+		bn := buildnumber.New(repo)
+
+		result, err := bn.Reconcile(t.Context(), "test", "origin", buildnumber.StrategyRemote)
+		assert.NoError(t, err)
+
+		assert.Equal(t, buildnumber.StateAhead, result.State)
+		assert.Equal(t, buildnumber.Entry{Number: 2, Hash: localHead.Hash}, *result.Entry)
+	})
+	t.Run("missing on remote", func(t *testing.T) {
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		_ = addRemote(t, "origin", true, repo)
+		bn := buildnumber.New(repo)
+		_, _ = bn.Set("test", user, email, 1)
+
+		result, err := bn.Reconcile(t.Context(), "test", "origin", buildnumber.StrategyRemote)
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, buildnumber.ErrBuildNumberNotFound)
+	})
+}
+
+func TestParseStrategy(t *testing.T) {
+	strategy, err := buildnumber.ParseStrategy("timestamp")
+	assert.NoError(t, err)
+	assert.Equal(t, buildnumber.StrategyTimestamp, strategy)
+
+	_, err = buildnumber.ParseStrategy("invalid")
+	assert.ErrorIs(t, err, buildnumber.ErrUnknownStrategy)
+}
@@ -0,0 +1,142 @@
+package buildnumber
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/anselstetter/git-build-number/internal/repository"
+)
+
+type State string
+
+const (
+	StateInSync     State = "in sync"
+	StateAhead      State = "ahead"
+	StateBehind     State = "behind"
+	StateDiverged   State = "diverged"
+	StateUnknown    State = "unknown"
+	StateLocalOnly  State = "local only"
+	StateRemoteOnly State = "remote only"
+)
+
+type Status struct {
+	Name   string
+	State  State
+	Local  *Entry
+	Remote *Entry
+}
+
+func (bn *BuildNumber) Status(ctx context.Context, remoteName string, filter Filter) ([]Status, error) {
+	remoteRefs, err := bn.repository.RemoteRefs(ctx, remoteName, repository.WithPrefix(bn.refName+"/"))
+	if err != nil {
+		return nil, err
+	}
+	if err := bn.FetchTracking(ctx, remoteName, filter, 0); err != nil {
+		return nil, err
+	}
+	localRefs, err := bn.repository.Refs(repository.WithPrefix(bn.refName + "/"))
+	if err != nil {
+		return nil, err
+	}
+	local := map[string]string{}
+	remote := map[string]string{}
+	names := []string{}
+
+	for _, ref := range localRefs {
+		name := bn.namespace(ref)
+		if !filter.Match(name) {
+			continue
+		}
+		local[name] = ref.Hash
+		names = append(names, name)
+	}
+	for _, ref := range remoteRefs {
+		name := bn.namespace(ref)
+		if !filter.Match(name) {
+			continue
+		}
+		remote[name] = ref.Hash
+		if _, ok := local[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	statuses := make([]Status, 0, len(names))
+	for _, name := range names {
+		status, err := bn.status(remoteName, name, local[name], remote[name])
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, *status)
+	}
+	return statuses, nil
+}
+
+func (bn *BuildNumber) status(remoteName string, name string, localHash string, remoteHash string) (*Status, error) {
+	status := Status{Name: name}
+
+	if localHash != "" {
+		entry, err := bn.Get(name, "", "", false)
+		if err != nil {
+			return nil, err
+		}
+		status.Local = entry
+	}
+	if remoteHash != "" {
+		entry, err := bn.entry(bn.trackingRef(remoteName, name))
+		if err != nil {
+			return nil, err
+		}
+		status.Remote = entry
+	}
+	switch {
+	case remoteHash == "":
+		status.State = StateLocalOnly
+	case localHash == "":
+		status.State = StateRemoteOnly
+	case localHash == remoteHash:
+		status.State = StateInSync
+	default:
+		ahead, err := bn.contains(bn.ref(name), remoteHash)
+		if err != nil && !errors.Is(err, repository.ErrShallowHistory) {
+			return nil, err
+		}
+		shallow := err != nil
+		behind, err := bn.contains(bn.trackingRef(remoteName, name), localHash)
+		if err != nil && !errors.Is(err, repository.ErrShallowHistory) {
+			return nil, err
+		}
+		shallow = shallow || err != nil
+		switch {
+		case ahead:
+			status.State = StateAhead
+		case behind:
+			status.State = StateBehind
+		case shallow:
+			status.State = StateUnknown
+		default:
+			status.State = StateDiverged
+		}
+	}
+	return &status, nil
+}
+
+func (bn *BuildNumber) entry(refName string) (*Entry, error) {
+	content, err := bn.repository.Content(refName, bn.fileName)
+	if err != nil && errors.Is(err, repository.ErrReferenceNotFound) {
+		return nil, errors.Join(err, ErrBuildNumberNotFound)
+	} else if err != nil {
+		return nil, err
+	}
+	return bn.unmarshal(*content)
+}
+
+func (bn *BuildNumber) contains(refName string, hash string) (bool, error) {
+	commits, err := bn.repository.Commits(refName, repository.WithHash(hash))
+	if err != nil {
+		return false, err
+	}
+	return len(commits) > 0, nil
+}
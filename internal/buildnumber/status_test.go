@@ -0,0 +1,116 @@
+package buildnumber_test
+
+import (
+	"testing"
+
+	"github.com/anselstetter/git-build-number/internal/buildnumber"
+	"github.com/anselstetter/git-build-number/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatus(t *testing.T) {
+	t.Run("without remote", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		statuses, err := bn.Status(t.Context(), "origin", buildnumber.Filter{})
+		assert.Nil(t, statuses)
+		assert.ErrorIs(t, err, repository.ErrRemoteNotFound)
+	})
+	t.Run("states", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		remote := addRemote(t, "origin", true, repo)
+
+		bnLocal := buildnumber.New(repo)
+		bnRemote := buildnumber.New(remote)
+
+		for _, namespace := range []string{"ahead", "behind", "diverged", "sync"} {
+			_, _ = bnRemote.Set(namespace, user, email, 1)
+		}
+		_ = bnLocal.Fetch(t.Context(), "origin", buildnumber.Filter{}, 0)
+
+		_, _ = bnLocal.Set("ahead", user, email, 2)
+		_, _ = bnRemote.Set("behind", user, email, 3)
+		_, _ = bnLocal.Set("diverged", user, email, 4)
+		_, _ = bnRemote.Set("diverged", user, email, 5)
+		_, _ = bnLocal.Set("local", user, email, 6)
+		_, _ = bnRemote.Set("remote", user, email, 7)
+
+		statuses, err := bnLocal.Status(t.Context(), "origin", buildnumber.Filter{})
+		assert.NoError(t, err)
+
+		type result struct {
+			name   string
+			state  buildnumber.State
+			local  int64
+			remote int64
+		}
+		results := []result{}
+		for _, status := range statuses {
+			r := result{name: status.Name, state: status.State}
+			if status.Local != nil {
+				r.local = status.Local.Number
+			}
+			if status.Remote != nil {
+				r.remote = status.Remote.Number
+			}
+			results = append(results, r)
+		}
+		expected := []result{
+			{name: "ahead", state: buildnumber.StateAhead, local: 2, remote: 1},
+			{name: "behind", state: buildnumber.StateBehind, local: 1, remote: 3},
+			{name: "diverged", state: buildnumber.StateDiverged, local: 4, remote: 5},
+			{name: "local", state: buildnumber.StateLocalOnly, local: 6},
+			{name: "remote", state: buildnumber.StateRemoteOnly, remote: 7},
+			{name: "sync", state: buildnumber.StateInSync, local: 1, remote: 1},
+		}
+		assert.Equal(t, expected, results)
+
+		entry, err := bnLocal.Get("behind", user, email, false)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), entry.Number, "local namespaces should be untouched")
+	})
+	t.Run("with filter", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		remote := addRemote(t, "origin", true, repo)
+
+		bnLocal := buildnumber.New(repo)
+		bnRemote := buildnumber.New(remote)
+
+		_, _ = bnLocal.Set("pr/1", user, email, 1)
+		_, _ = bnRemote.Set("prod", user, email, 2)
+
+		statuses, err := bnLocal.Status(t.Context(), "origin", buildnumber.Filter{Include: []string{"pr/*"}})
+		assert.NoError(t, err)
+		assert.Len(t, statuses, 1)
+		assert.Equal(t, "pr/1", statuses[0].Name)
+		assert.Equal(t, buildnumber.StateLocalOnly, statuses[0].State)
+	})
+}
+
+func TestFetchTracking(t *testing.T) {
+	repo, _, _ := repository.NewGitInMemoryRepository(true)
+	remote := addRemote(t, "origin", true, repo)
+
+	bnLocal := buildnumber.New(repo)
+	bnRemote := buildnumber.New(remote)
+
+	_, _ = bnLocal.Set("test", user, email, 1)
+	_, _ = bnRemote.Set("test", user, email, 123)
+
+	err := bnLocal.FetchTracking(t.Context(), "origin", buildnumber.Filter{}, 0)
+	assert.NoError(t, err)
+
+	entry, _ := bnLocal.Get("test", user, email, false)
+	assert.Equal(t, int64(1), entry.Number)
+
+	refs, _ := repo.Refs(repository.WithPrefix("refs/remotes/origin/build-number/"))
+	assert.Len(t, refs, 1)
+	assert.Equal(t, "refs/remotes/origin/build-number/test", refs[0].Path)
+}
@@ -2,9 +2,12 @@ package cmd
 
 import (
 	"bufio"
+	"fmt"
 	"io"
+	"slices"
 	"strings"
 
+	"github.com/anselstetter/git-build-number/internal/buildnumber"
 	"github.com/anselstetter/git-build-number/internal/logger"
 )
 
@@ -14,14 +17,23 @@ func confirm(logger logger.Logger, msg string, confirmation string, reader io.Re
 	logger.Stdoutln(msg)
 	for {
 		logger.Stdoutf("%s (y/N) ", confirmation)
-		text, _ := buf.ReadString('\n')
-		answer := strings.ToLower(strings.TrimSuffix(text, "\n"))
+		text, err := buf.ReadString('\n')
+		answer := strings.ToLower(strings.TrimSpace(text))
 
 		if answer == "y" {
 			return true
 		}
-		if answer == "n" {
+		if answer == "n" || err != nil {
 			return false
 		}
 	}
 }
+
+func guardProtected(protected []string, yes bool, explicit []string) error {
+	for _, namespace := range protected {
+		if !yes || !slices.Contains(explicit, namespace) {
+			return fmt.Errorf("%w: %s (requires --yes and the namespace name)", buildnumber.ErrProtected, namespace)
+		}
+	}
+	return nil
+}
@@ -6,4 +6,16 @@ var (
 	ErrMissingBuildNumber = errors.New("please provide a build number")
 	ErrInvalidNumber      = errors.New("not a valid number")
 	ErrMissingNamespace   = errors.New("please provide a namespace")
+	ErrMissingFormat      = errors.New("please provide a format")
+	ErrMissingFile        = errors.New("please provide a file")
+	ErrMissingCommand     = errors.New("please provide a command")
+	ErrReservationLost    = errors.New("reserved build number was taken")
+	ErrUnknownSort        = errors.New("unknown sort")
+	ErrUnknownFormat      = errors.New("unknown format")
+	ErrRemoteURLCommand   = errors.New("--remote-url only supports inc and next")
+	ErrRemoteURLRev       = errors.New("--remote-url requires --rev")
+	ErrClientKey          = errors.New("--client-key requires --client-cert")
+	ErrUnknownBackend     = errors.New("unknown backend")
+	ErrTimeout            = errors.New("timed out")
+	ErrInterrupted        = errors.New("interrupted")
 )
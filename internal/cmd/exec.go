@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
+
+	"github.com/anselstetter/git-build-number/internal/buildnumber"
+	"github.com/anselstetter/git-build-number/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+const synopsisExec = `Reserve the next build number and run a command with it.
+
+The command gets BUILD_NUMBER, BUILD_NUMBER_HASH and BUILD_NUMBER_NAMESPACE in its environment.
+The build number is only committed if the command exits with 0. With --push (the default) it is
+computed from the remote and pushed there afterwards, so a failed run releases the number.
+If another run took the number in the meantime, nothing is committed and exec fails.
+The exit code of the command is passed through, 128 + the signal if it was killed by one.`
+
+type ExitCodeError struct {
+	Code int
+}
+
+func (e ExitCodeError) Error() string {
+	return fmt.Sprintf("command exited with code %d", e.Code)
+}
+
+func NewExecCommand(buildNumber buildnumber.BuildNumber, logger logger.Logger, reader io.Reader) *cobra.Command {
+	var (
+		namespace string
+		user      string
+		email     string
+		remote    string
+		force     bool
+		push      bool
+	)
+	cmd := &cobra.Command{
+		Use:   "exec -- <command> [args]...",
+		Short: "Run a command with the next build number",
+		Long:  synopsisExec,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return ErrMissingCommand
+			}
+			return nil
+		},
+		RunE: SilenceUsageE(func(cmd *cobra.Command, args []string) error {
+			return Exec(cmd.Context(), buildNumber, logger, reader, namespace, user, email, remote, force, push, args...)
+		}),
+	}
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "the namespace")
+	cmd.Flags().StringVarP(&user, "user", "u", "build number", "the author name")
+	cmd.Flags().StringVarP(&email, "email", "e", "not set", "the author email")
+	cmd.Flags().StringVarP(&remote, "remote", "r", "origin", "the remote")
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "force")
+	cmd.Flags().BoolVarP(&push, "push", "p", true, "push the build number after a successful run")
+	return cmd
+}
+
+func Exec(ctx context.Context, buildNumber buildnumber.BuildNumber, logger logger.Logger, reader io.Reader, namespace string, user string, email string, remote string, force bool, push bool, args ...string) error {
+	next := func() (*buildnumber.Entry, bool, error) {
+		return buildNumber.Next(namespace, force)
+	}
+	inc := func() (*buildnumber.Entry, bool, error) {
+		return buildNumber.Inc(namespace, user, email, force)
+	}
+	if push {
+		next = func() (*buildnumber.Entry, bool, error) {
+			return buildNumber.NextRemote(ctx, namespace, remote, force)
+		}
+		inc = func() (*buildnumber.Entry, bool, error) {
+			return buildNumber.IncRemote(ctx, namespace, remote, user, email, force)
+		}
+	}
+	metadata, err := buildNumber.Metadata(namespace)
+	if err != nil {
+		return err
+	}
+	if metadata.Frozen {
+		return fmt.Errorf("%w: %s", buildnumber.ErrFrozen, namespace)
+	}
+	reserved, _, err := next()
+	if err != nil {
+		return err
+	}
+	if err := run(ctx, logger, reader, namespace, reserved, args...); err != nil {
+		return err
+	}
+	current, _, err := next()
+	if err != nil {
+		return err
+	}
+	if current.Number != reserved.Number {
+		return fmt.Errorf("%w: %d was reserved, %d is next", ErrReservationLost, reserved.Number, current.Number)
+	}
+	committed, _, err := inc()
+	if err != nil {
+		return err
+	}
+	if committed.Number != reserved.Number {
+		return fmt.Errorf("%w: %d was reserved, %d was committed", ErrReservationLost, reserved.Number, committed.Number)
+	}
+	return nil
+}
+
+func run(ctx context.Context, logger logger.Logger, reader io.Reader, namespace string, reserved *buildnumber.Entry, args ...string) error {
+	child := exec.CommandContext(ctx, args[0], args[1:]...)
+	child.Stdin = reader
+	child.Stdout = logger.StdoutWriter()
+	child.Stderr = logger.StderrWriter()
+	child.Env = append(os.Environ(),
+		"BUILD_NUMBER="+strconv.FormatInt(reserved.Number, 10),
+		"BUILD_NUMBER_HASH="+reserved.Hash,
+		"BUILD_NUMBER_NAMESPACE="+namespace,
+	)
+	err := child.Run()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return err
+	}
+	if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		return ExitCodeError{Code: 128 + int(status.Signal())}
+	}
+	return ExitCodeError{Code: exitErr.ExitCode()}
+}
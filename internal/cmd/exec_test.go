@@ -0,0 +1,212 @@
+package cmd_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anselstetter/git-build-number/internal/buildnumber"
+	"github.com/anselstetter/git-build-number/internal/cmd"
+	"github.com/anselstetter/git-build-number/internal/logger"
+	"github.com/anselstetter/git-build-number/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExec(t *testing.T) {
+	silence := bytes.NewBuffer([]byte{})
+
+	t.Run("without command", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(stderr))
+
+		c := cmd.NewExecCommand(bn, logger, strings.NewReader(""))
+		c.SetOut(silence)
+		c.SetErr(silence)
+
+		err := c.Execute()
+
+		assert.ErrorIs(t, err, cmd.ErrMissingCommand)
+		assert.Equal(t, "", stdout.String())
+		assert.Equal(t, "", stderr.String())
+	})
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+		bn.Set("test", "user", "email@domain.tld", 4) // nolint:errcheck
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(stderr))
+
+		c := cmd.NewExecCommand(bn, logger, strings.NewReader(""))
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"--namespace", "test", "--force", "--push=false", "--", "sh", "-c", "echo $BUILD_NUMBER $BUILD_NUMBER_NAMESPACE"})
+
+		err := c.Execute()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "5 test\n", stdout.String())
+		assert.Equal(t, "", stderr.String())
+
+		entry, err := bn.Get("test", "", "", false)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), entry.Number)
+	})
+	t.Run("failure", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(stderr))
+
+		c := cmd.NewExecCommand(bn, logger, strings.NewReader(""))
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"--push=false", "--", "sh", "-c", "echo $BUILD_NUMBER; exit 3"})
+
+		err := c.Execute()
+
+		assert.ErrorIs(t, err, cmd.ExitCodeError{Code: 3})
+		assert.Equal(t, "1\n", stdout.String())
+		assert.Equal(t, "", stderr.String())
+
+		_, err = bn.Get("default", "", "", false)
+		assert.ErrorIs(t, err, buildnumber.ErrBuildNumberNotFound)
+	})
+	t.Run("killed by a signal", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		logger := logger.New(logger.WithStdout(silence), logger.WithStderr(silence))
+
+		c := cmd.NewExecCommand(bn, logger, strings.NewReader(""))
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"--push=false", "--", "sh", "-c", "kill -TERM $$"})
+
+		err := c.Execute()
+
+		assert.ErrorIs(t, err, cmd.ExitCodeError{Code: 143})
+	})
+	t.Run("canceled", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		logger := logger.New(logger.WithStdout(silence), logger.WithStderr(silence))
+
+		c := cmd.NewExecCommand(bn, logger, strings.NewReader(""))
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"--push=false", "--", "sleep", "10"})
+
+		ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+
+		err := c.ExecuteContext(ctx)
+
+		assert.ErrorIs(t, err, cmd.ExitCodeError{Code: 137})
+		assert.Less(t, time.Since(start), 5*time.Second)
+
+		_, err = bn.Get("default", "", "", false)
+		assert.ErrorIs(t, err, buildnumber.ErrBuildNumberNotFound)
+	})
+	t.Run("frozen", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+		bn.Set("default", "user", "email@domain.tld", 4)                                          // nolint:errcheck
+		bn.SetMetadata("default", "user", "email@domain.tld", buildnumber.Metadata{Frozen: true}) // nolint:errcheck
+
+		stdout := bytes.NewBuffer([]byte{})
+
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(silence))
+
+		c := cmd.NewExecCommand(bn, logger, strings.NewReader(""))
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"--force", "--push=false", "--", "echo", "ran"})
+
+		err := c.Execute()
+
+		assert.ErrorIs(t, err, buildnumber.ErrFrozen)
+		assert.Equal(t, "", stdout.String())
+	})
+	t.Run("pushes after a successful run", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		remote := addRemote(t, "origin", true, repo)
+		bnRemote := buildnumber.New(remote)
+		bnRemote.Set("default", "user", "email@domain.tld", 41) // nolint:errcheck
+		bn := buildnumber.New(repo)
+
+		stdout := bytes.NewBuffer([]byte{})
+
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(silence))
+
+		c := cmd.NewExecCommand(bn, logger, strings.NewReader(""))
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"--force", "--", "sh", "-c", "echo $BUILD_NUMBER; exit 3"})
+
+		err := c.Execute()
+
+		assert.ErrorIs(t, err, cmd.ExitCodeError{Code: 3})
+		assert.Equal(t, "42\n", stdout.String())
+
+		entry, err := bnRemote.Get("default", "", "", false)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(41), entry.Number)
+		_, err = bn.Get("default", "", "", false)
+		assert.ErrorIs(t, err, buildnumber.ErrBuildNumberNotFound)
+
+		stdout.Reset()
+		c.SetArgs([]string{"--force", "--", "sh", "-c", "echo $BUILD_NUMBER"})
+		assert.NoError(t, c.Execute())
+		assert.Equal(t, "42\n", stdout.String())
+
+		entry, err = bnRemote.Get("default", "", "", false)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(42), entry.Number)
+	})
+	t.Run("push without remote", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		logger := logger.New(logger.WithStdout(silence), logger.WithStderr(silence))
+
+		c := cmd.NewExecCommand(bn, logger, strings.NewReader(""))
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"--", "true"})
+
+		err := c.Execute()
+
+		assert.ErrorIs(t, err, repository.ErrRemoteNotFound)
+	})
+}
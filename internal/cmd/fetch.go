@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"context"
+
 	"github.com/anselstetter/git-build-number/internal/buildnumber"
 	"github.com/anselstetter/git-build-number/internal/logger"
 	"github.com/spf13/cobra"
@@ -8,23 +10,35 @@ import (
 
 func NewFetchCommand(buildNumber buildnumber.BuildNumber, logger logger.Logger) *cobra.Command {
 	var (
-		remote string
+		remote     string
+		namespaces []string
+		exclude    []string
+		tracking   bool
+		depth      int
 	)
 	cmd := &cobra.Command{
 		Use:    "fetch",
 		Short:  "Fetch build number(s)",
 		PreRun: IgnoreAdditonalArgs(logger.StderrWriter(), 1),
 		RunE: SilenceUsageE(func(cmd *cobra.Command, args []string) error {
-			return Fetch(buildNumber, logger, remote)
+			filter := buildnumber.Filter{Include: namespaces, Exclude: exclude}
+			return Fetch(cmd.Context(), buildNumber, logger, remote, filter, tracking, depth)
 		}),
 	}
 	cmd.Flags().StringVarP(&remote, "remote", "r", "origin", "the remote")
+	cmd.Flags().StringArrayVarP(&namespaces, "namespace", "n", []string{}, "only these namespaces or globs (pr/*, release/**)")
+	cmd.Flags().StringArrayVar(&exclude, "exclude", []string{}, "skip these namespaces or globs")
+	cmd.Flags().BoolVarP(&tracking, "tracking", "t", false, "fetch into refs/remotes/<remote>/build-number/* instead")
+	cmd.Flags().IntVar(&depth, "depth", 0, "only fetch this many commits of each namespace")
 
 	return cmd
 }
 
-func Fetch(buildNumber buildnumber.BuildNumber, logger logger.Logger, remote string) error {
-	err := buildNumber.Fetch(remote)
+func Fetch(ctx context.Context, buildNumber buildnumber.BuildNumber, logger logger.Logger, remote string, filter buildnumber.Filter, tracking bool, depth int) error {
+	if tracking {
+		return buildNumber.FetchTracking(ctx, remote, filter, depth)
+	}
+	err := buildNumber.Fetch(ctx, remote, filter, depth)
 	if err != nil {
 		return err
 	}
@@ -35,4 +35,129 @@ func TestFetch(t *testing.T) {
 		assert.Equal(t, "", stdout.String())
 		assert.Equal(t, "", stderr.String())
 	})
+	t.Run("--namespace", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		remote := addRemote(t, "origin", true, repo)
+		bnRemote := buildnumber.New(remote)
+		bnRemote.Set("pr/1", "user", "email@domain.tld", 1) // nolint:errcheck
+		bnRemote.Set("prod", "user", "email@domain.tld", 2) // nolint:errcheck
+		bn := buildnumber.New(repo)
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(stderr))
+
+		c := cmd.NewFetchCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"--namespace", "pr/*"})
+
+		err := c.Execute()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "", stdout.String())
+		assert.Equal(t, "", stderr.String())
+
+		namespaces, _ := bn.Namespaces()
+		assert.Len(t, namespaces, 1)
+		assert.Equal(t, "pr/1", namespaces[0].Name)
+	})
+	t.Run("--tracking", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		remote := addRemote(t, "origin", true, repo)
+		bnRemote := buildnumber.New(remote)
+		bnRemote.Set("test", "user", "email@domain.tld", 1) // nolint:errcheck
+		bn := buildnumber.New(repo)
+
+		logger := logger.New(logger.WithStdout(silence), logger.WithStderr(silence))
+
+		c := cmd.NewFetchCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"--tracking"})
+
+		err := c.Execute()
+
+		assert.NoError(t, err)
+
+		namespaces, _ := bn.Namespaces()
+		assert.Empty(t, namespaces)
+
+		refs, _ := repo.Refs(repository.WithPrefix("refs/remotes/origin/build-number/"))
+		assert.Len(t, refs, 1)
+	})
+	t.Run("--depth", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		remote := addRemote(t, "origin", true, repo)
+		bnRemote := buildnumber.New(remote)
+		bnRemote.Set("test", "user", "email@domain.tld", 1) // nolint:errcheck
+		bn := buildnumber.New(repo)
+
+		logger := logger.New(logger.WithStdout(silence), logger.WithStderr(silence))
+
+		c := cmd.NewFetchCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"--depth", "1"})
+
+		err := c.Execute()
+
+		assert.NoError(t, err)
+
+		entry, err := bn.Get("test", "", "", false)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), entry.Number)
+	})
+	t.Run("--depth with history", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		remote := buildnumber.New(addRemote(t, "origin", true, repo))
+		for range 3 {
+			remote.Inc("test", "user", "email@domain.tld", true) // nolint:errcheck
+		}
+		bn := buildnumber.New(repo)
+
+		logger := logger.New(logger.WithStdout(silence), logger.WithStderr(silence))
+
+		c := cmd.NewFetchCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"--depth", "1"})
+
+		err := c.Execute()
+
+		assert.NoError(t, err)
+
+		_, err = repo.Commits("refs/build-number/test")
+		assert.ErrorIs(t, err, repository.ErrShallowHistory)
+
+		entry, err := bn.Get("test", "", "", false)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(4), entry.Number)
+
+		_, err = bn.Hash("test", 4)
+		assert.NoError(t, err)
+		_, err = bn.Hash("test", 1)
+		assert.ErrorIs(t, err, repository.ErrShallowHistory)
+
+		c = cmd.NewFetchCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+
+		err = c.Execute()
+
+		assert.NoError(t, err)
+
+		commits, err := repo.Commits("refs/build-number/test")
+		assert.NoError(t, err)
+		assert.Len(t, commits, 4)
+	})
 }
@@ -12,25 +12,36 @@ func NewGetCommand(buildNumber buildnumber.BuildNumber, logger logger.Logger) *c
 		user      string
 		email     string
 		create    bool
+		at        string
 	)
 	cmd := &cobra.Command{
 		Use:    "get",
 		Short:  "Get the latest build number",
 		PreRun: IgnoreAdditonalArgs(logger.StderrWriter(), 1),
 		RunE: SilenceUsageE(func(cmd *cobra.Command, args []string) error {
-			return Get(buildNumber, logger, namespace, user, email, create)
+			return Get(buildNumber, logger, namespace, user, email, create, at)
 		}),
 	}
 	cmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "the namespace")
 	cmd.Flags().StringVarP(&user, "user", "u", "build number", "the author name")
 	cmd.Flags().StringVarP(&email, "email", "e", "not set", "the author email")
 	cmd.Flags().BoolVarP(&create, "create", "c", false, "create if missing")
+	cmd.Flags().StringVar(&at, "at", "", "get the build number of this commit")
 
 	return cmd
 }
 
-func Get(buildNumber buildnumber.BuildNumber, logger logger.Logger, namespace string, user string, email string, create bool) error {
-	entry, err := buildNumber.Get(namespace, user, email, create)
+func Get(buildNumber buildnumber.BuildNumber, logger logger.Logger, namespace string, user string, email string, create bool, at string) error {
+	var (
+		entry *buildnumber.Entry
+		err   error
+	)
+	if at != "" {
+		buildNumber = buildNumber.At(at)
+		entry, err = buildNumber.Number(namespace)
+	} else {
+		entry, err = buildNumber.Get(namespace, user, email, create)
+	}
 	if err != nil {
 		return err
 	}
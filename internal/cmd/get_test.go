@@ -103,4 +103,26 @@ func TestGet(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, repository.Author{Name: "First Last", Email: "email@test.tld"}, commits[0].Author)
 	})
+	t.Run("--at", func(t *testing.T) {
+		t.Parallel()
+
+		repo, initial, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+		bn.Set("default", "user", "email@domain.tld", 123) // nolint:errcheck
+		_, _ = repo.Commit("refs/heads/main", "next", []byte("next"), "Next commit", repository.WithHead())
+		bn.Inc("default", "user", "email@domain.tld", false) // nolint:errcheck
+
+		stdout := bytes.NewBuffer([]byte{})
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(silence))
+
+		c := cmd.NewGetCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"--at", initial.Hash[:7]})
+
+		err := c.Execute()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "123\n", stdout.String())
+	})
 }
@@ -25,3 +25,13 @@ func SilenceUsageE(f func(cmd *cobra.Command, args []string) error) func(cmd *co
 		return f(cmd, args)
 	}
 }
+
+func SilenceUsageOnErrorE(f func(cmd *cobra.Command, args []string) error) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		err := f(cmd, args)
+		if err != nil {
+			cmd.SilenceUsage = true
+		}
+		return err
+	}
+}
@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"context"
+
 	"github.com/anselstetter/git-build-number/internal/buildnumber"
 	"github.com/anselstetter/git-build-number/internal/logger"
 	"github.com/spf13/cobra"
@@ -12,25 +14,41 @@ func NewIncCommand(buildNumber buildnumber.BuildNumber, logger logger.Logger) *c
 		user      string
 		email     string
 		force     bool
+		rev       string
+		remote    string
 	)
 	cmd := &cobra.Command{
 		Use:    "inc",
 		Short:  "Increment the build number",
 		PreRun: IgnoreAdditonalArgs(logger.StderrWriter(), 1),
 		RunE: SilenceUsageE(func(cmd *cobra.Command, args []string) error {
-			return Inc(buildNumber, logger, namespace, user, email, force)
+			if remote == "" && RemoteURL(cmd) != "" {
+				remote = RemoteURLName
+			}
+			return Inc(cmd.Context(), buildNumber.At(rev), logger, namespace, user, email, force, remote)
 		}),
 	}
 	cmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "the namespace")
 	cmd.Flags().StringVarP(&user, "user", "u", "build number", "the author name")
 	cmd.Flags().StringVarP(&email, "email", "e", "not set", "the author email")
 	cmd.Flags().BoolVarP(&force, "force", "f", false, "force")
+	cmd.Flags().StringVar(&rev, "rev", "", "number this commit instead of HEAD")
+	cmd.Flags().StringVarP(&remote, "remote", "r", "", "fetch, increment and push the build number of this remote")
 
 	return cmd
 }
 
-func Inc(buildNumber buildnumber.BuildNumber, logger logger.Logger, namespace string, user string, email string, force bool) error {
-	entry, updated, err := buildNumber.Inc(namespace, user, email, force)
+func Inc(ctx context.Context, buildNumber buildnumber.BuildNumber, logger logger.Logger, namespace string, user string, email string, force bool, remote string) error {
+	var (
+		entry   *buildnumber.Entry
+		updated bool
+		err     error
+	)
+	if remote != "" {
+		entry, updated, err = buildNumber.IncRemote(ctx, namespace, remote, user, email, force)
+	} else {
+		entry, updated, err = buildNumber.Inc(namespace, user, email, force)
+	}
 	if err != nil {
 		return err
 	}
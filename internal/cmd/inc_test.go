@@ -79,4 +79,32 @@ func TestInc(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, repository.Author{Name: "First Last", Email: "email@test.tld"}, commits[0].Author)
 	})
+	t.Run("--remote", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		remote := addRemote(t, "origin", true, repo)
+		bnRemote := buildnumber.New(remote)
+		bnRemote.Set("default", "user", "email@domain.tld", 41)                                      // nolint:errcheck
+		repo.Commit("refs/heads/main", "next", []byte("next"), "Next commit", repository.WithHead()) // nolint:errcheck
+		bn := buildnumber.New(repo)
+
+		stdout := bytes.NewBuffer([]byte{})
+
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(silence))
+
+		c := cmd.NewIncCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"--remote", "origin"})
+
+		err := c.Execute()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "42\n", stdout.String())
+
+		entry, err := bnRemote.Get("default", "user", "email@domain.tld", false)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(42), entry.Number)
+	})
 }
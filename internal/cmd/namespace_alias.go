@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"github.com/anselstetter/git-build-number/internal/buildnumber"
+	"github.com/anselstetter/git-build-number/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+const synopsisNamespaceAlias = `Manage namespace aliases.
+
+An alias is a symbolic ref (refs/build-number/<alias> -> refs/build-number/<namespace>),
+get, inc, set and hash on the alias work on the target namespace. Aliases are local
+and not pushed.`
+
+func NewNamespaceAliasCommand(cmds ...*cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "alias",
+		Short: "Manage namespace aliases",
+		Long:  synopsisNamespaceAlias,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(cmds...)
+
+	return cmd
+}
+
+func NewNamespaceAliasSetCommand(buildNumber buildnumber.BuildNumber, logger logger.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set <alias> <namespace>",
+		Short: "Point an alias to a namespace",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 2 {
+				return ErrMissingNamespace
+			}
+			return nil
+		},
+		RunE: SilenceUsageE(func(cmd *cobra.Command, args []string) error {
+			return SetAlias(buildNumber, logger, args[0], args[1])
+		}),
+	}
+	return cmd
+}
+
+func NewNamespaceAliasRemoveCommand(buildNumber buildnumber.BuildNumber, logger logger.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove <alias>...",
+		Short: "Remove an alias",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return ErrMissingNamespace
+			}
+			return nil
+		},
+		RunE: SilenceUsageE(func(cmd *cobra.Command, args []string) error {
+			return RemoveAlias(buildNumber, logger, args...)
+		}),
+	}
+	return cmd
+}
+
+func NewNamespaceAliasListCommand(buildNumber buildnumber.BuildNumber, logger logger.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:    "list",
+		Short:  "List all aliases",
+		PreRun: IgnoreAdditonalArgs(logger.StderrWriter(), 1),
+		RunE: SilenceUsageE(func(cmd *cobra.Command, args []string) error {
+			return ListAliases(buildNumber, logger)
+		}),
+	}
+	return cmd
+}
+
+func SetAlias(buildNumber buildnumber.BuildNumber, logger logger.Logger, alias string, namespace string) error {
+	return buildNumber.Alias(alias, namespace)
+}
+
+func RemoveAlias(buildNumber buildnumber.BuildNumber, logger logger.Logger, aliases ...string) error {
+	for _, alias := range aliases {
+		if err := buildNumber.Unalias(alias); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func ListAliases(buildNumber buildnumber.BuildNumber, logger logger.Logger) error {
+	aliases, err := buildNumber.Aliases()
+	if err != nil {
+		return err
+	}
+	out := []any{}
+	for _, alias := range aliases {
+		out = append(out, alias.Name)
+		out = append(out, alias.Target)
+	}
+	logger.StdoutTable(out...)
+	return nil
+}
@@ -0,0 +1,103 @@
+package cmd_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/anselstetter/git-build-number/internal/buildnumber"
+	"github.com/anselstetter/git-build-number/internal/cmd"
+	"github.com/anselstetter/git-build-number/internal/logger"
+	"github.com/anselstetter/git-build-number/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamespaceAlias(t *testing.T) {
+	silence := bytes.NewBuffer([]byte{})
+
+	t.Run("set without args", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		logger := logger.New(logger.WithStdout(silence), logger.WithStderr(silence))
+
+		c := cmd.NewNamespaceAliasSetCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"current"})
+
+		err := c.Execute()
+
+		assert.ErrorIs(t, err, cmd.ErrMissingNamespace)
+	})
+	t.Run("set and list", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+		bn.Set("release/2026.10", "user", "email@domain.tld", 3) // nolint:errcheck
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(stderr))
+
+		c := cmd.NewNamespaceAliasCommand(
+			cmd.NewNamespaceAliasSetCommand(bn, logger),
+			cmd.NewNamespaceAliasListCommand(bn, logger),
+		)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"set", "current-release", "release/2026.10"})
+
+		err := c.Execute()
+		assert.NoError(t, err)
+
+		c.SetArgs([]string{"list"})
+
+		err = c.Execute()
+		assert.NoError(t, err)
+
+		assert.Equal(t, "current-release release/2026.10\n", stdout.String())
+		assert.Equal(t, "", stderr.String())
+	})
+	t.Run("remove", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+		bn.Set("release/2026.10", "user", "email@domain.tld", 3) // nolint:errcheck
+		bn.Alias("current-release", "release/2026.10")           // nolint:errcheck
+
+		logger := logger.New(logger.WithStdout(silence), logger.WithStderr(silence))
+
+		c := cmd.NewNamespaceAliasRemoveCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"current-release"})
+
+		err := c.Execute()
+		assert.NoError(t, err)
+
+		aliases, _ := bn.Aliases()
+		assert.Empty(t, aliases)
+	})
+	t.Run("remove namespace", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+		bn.Set("release/2026.10", "user", "email@domain.tld", 3) // nolint:errcheck
+
+		logger := logger.New(logger.WithStdout(silence), logger.WithStderr(silence))
+
+		c := cmd.NewNamespaceAliasRemoveCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"release/2026.10"})
+
+		err := c.Execute()
+		assert.ErrorIs(t, err, buildnumber.ErrNotAnAlias)
+	})
+}
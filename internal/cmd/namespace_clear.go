@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
 	"io"
 
 	"github.com/anselstetter/git-build-number/internal/buildnumber"
@@ -10,23 +12,62 @@ import (
 
 func NewNamespaceClearCommand(buildNumber buildnumber.BuildNumber, logger logger.Logger, reader io.Reader) *cobra.Command {
 	var (
-		yes bool
+		remote    string
+		yes       bool
+		protected []string
 	)
 	cmd := &cobra.Command{
 		Use:   "clear",
 		Short: "Delete all namespaces",
 		RunE: SilenceUsageE(func(cmd *cobra.Command, args []string) error {
-			return Clear(buildNumber, logger, yes, reader)
+			if remote != "" {
+				return ClearRemote(cmd.Context(), buildNumber, logger, remote, yes, protected, reader)
+			}
+			return Clear(buildNumber, logger, yes, protected, reader)
 		}),
 	}
+	cmd.Flags().StringVarP(&remote, "remote", "r", "", "delete all namespaces on the remote instead of locally")
 	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "I know what I’m doing")
+	cmd.Flags().StringArrayVar(&protected, "protected", []string{}, "also delete this protected namespace (requires --yes)")
 
 	return cmd
 }
 
-func Clear(buildNumber buildnumber.BuildNumber, logger logger.Logger, yes bool, reader io.Reader) error {
+func Clear(buildNumber buildnumber.BuildNumber, logger logger.Logger, yes bool, explicit []string, reader io.Reader) error {
 	if yes || confirm(logger, "All local namespaces will be deleted!\nUse --yes to skip the confirmation prompt.\n", "Continue?", reader) {
+		namespaces, err := buildNumber.Namespaces()
+		if err != nil {
+			return err
+		}
+		names := []string{}
+		for _, ns := range namespaces {
+			if ns.Target == "" {
+				names = append(names, ns.Name)
+			}
+		}
+		protected, err := buildNumber.Protected(names...)
+		if err != nil {
+			return err
+		}
+		if err := guardProtected(protected, yes, explicit); err != nil {
+			return err
+		}
 		return buildNumber.Clear()
 	}
 	return nil
 }
+
+func ClearRemote(ctx context.Context, buildNumber buildnumber.BuildNumber, logger logger.Logger, remote string, yes bool, explicit []string, reader io.Reader) error {
+	msg := fmt.Sprintf("All namespaces on remote %s will be deleted!\nUse --yes to skip the confirmation prompt.\n", remote)
+	if yes || confirm(logger, msg, "Continue?", reader) {
+		protected, err := buildNumber.ProtectedRemote(ctx, remote, buildnumber.Filter{})
+		if err != nil {
+			return err
+		}
+		if err := guardProtected(protected, yes, explicit); err != nil {
+			return err
+		}
+		return buildNumber.ClearRemote(ctx, remote)
+	}
+	return nil
+}
@@ -78,6 +78,28 @@ func TestNamespaceClear(t *testing.T) {
 		assert.True(t, len(stdout.String()) > 0, "should ask for confirmation")
 		assert.Equal(t, "", stderr.String())
 	})
+	t.Run("closed stdin", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+		bn.Set("test", "user", "email@domain.tld", 1) // nolint:errcheck
+
+		stdout := bytes.NewBuffer([]byte{})
+
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(silence))
+
+		c := cmd.NewNamespaceClearCommand(bn, logger, strings.NewReader("maybe\n"))
+		c.SetOut(silence)
+		c.SetErr(silence)
+
+		err := c.Execute()
+		assert.NoError(t, err)
+
+		_, err = bn.Get("test", "", "", false)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, strings.Count(stdout.String(), "Continue? (y/N)"))
+	})
 	t.Run("--yes", func(t *testing.T) {
 		t.Parallel()
 
@@ -107,6 +129,40 @@ func TestNamespaceClear(t *testing.T) {
 			assert.Nil(t, ns)
 		}
 
+		assert.Equal(t, "", stdout.String())
+		assert.Equal(t, "", stderr.String())
+	})
+	t.Run("with remote --yes", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		remote := addRemote(t, "origin", true, repo)
+		bn := buildnumber.New(repo)
+		bn.Set("test", "user", "email@domain.tld", 2) // nolint:errcheck
+		bnRemote := buildnumber.New(remote)
+		bnRemote.Set("test", "user", "email@domain.tld", 1)  // nolint:errcheck
+		bnRemote.Set("other", "user", "email@domain.tld", 4) // nolint:errcheck
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(stderr))
+
+		c := cmd.NewNamespaceClearCommand(bn, logger, strings.NewReader(""))
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"--remote", "origin", "--yes"})
+
+		err := c.Execute()
+		assert.NoError(t, err)
+
+		ns, _ := bnRemote.Namespaces()
+		assert.Equal(t, []buildnumber.Namespace{}, ns)
+
+		local, err := bn.Get("test", "", "", false)
+		assert.NoError(t, err)
+		assert.NotNil(t, local)
+
 		assert.Equal(t, "", stdout.String())
 		assert.Equal(t, "", stderr.String())
 	})
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/anselstetter/git-build-number/internal/buildnumber"
+	"github.com/anselstetter/git-build-number/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+const synopsisNamespaceCopy = `Copy a namespace including its counter.
+
+By default the new namespace starts with the full history of the source namespace,
+with --seed-only it only starts with the current build number. Either way a commit
+noting the source namespace is recorded. With --remote the new namespace is pushed,
+unless it already exists on the remote.`
+
+func NewNamespaceCopyCommand(buildNumber buildnumber.BuildNumber, logger logger.Logger) *cobra.Command {
+	var (
+		user     string
+		email    string
+		history  bool
+		seedOnly bool
+		remote   string
+	)
+	cmd := &cobra.Command{
+		Use:   "copy <src> <dst>",
+		Short: "Copy a namespace",
+		Long:  synopsisNamespaceCopy,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 2 {
+				return ErrMissingNamespace
+			}
+			return nil
+		},
+		RunE: SilenceUsageE(func(cmd *cobra.Command, args []string) error {
+			return Copy(cmd.Context(), buildNumber, logger, args[0], args[1], user, email, history && !seedOnly, remote)
+		}),
+	}
+	cmd.Flags().StringVarP(&user, "user", "u", "build number", "the author name")
+	cmd.Flags().StringVarP(&email, "email", "e", "not set", "the author email")
+	cmd.Flags().BoolVar(&history, "history", true, "keep the history of the source namespace")
+	cmd.Flags().BoolVar(&seedOnly, "seed-only", false, "only keep the current build number of the source namespace")
+	cmd.Flags().StringVarP(&remote, "remote", "r", "", "push the new namespace to this remote")
+	cmd.MarkFlagsMutuallyExclusive("history", "seed-only")
+
+	return cmd
+}
+
+func Copy(ctx context.Context, buildNumber buildnumber.BuildNumber, logger logger.Logger, src string, dst string, user string, email string, history bool, remote string) error {
+	entry, err := buildNumber.Copy(ctx, src, dst, user, email, history, remote)
+	if err != nil {
+		return err
+	}
+	logger.Stdoutln(entry.Number)
+	return nil
+}
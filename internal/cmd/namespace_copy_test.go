@@ -0,0 +1,78 @@
+package cmd_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/anselstetter/git-build-number/internal/buildnumber"
+	"github.com/anselstetter/git-build-number/internal/cmd"
+	"github.com/anselstetter/git-build-number/internal/logger"
+	"github.com/anselstetter/git-build-number/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamespaceCopy(t *testing.T) {
+	silence := bytes.NewBuffer([]byte{})
+
+	t.Run("without args", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		logger := logger.New(logger.WithStdout(silence), logger.WithStderr(silence))
+
+		c := cmd.NewNamespaceCopyCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"prod"})
+
+		err := c.Execute()
+
+		assert.ErrorIs(t, err, cmd.ErrMissingNamespace)
+	})
+	t.Run("--history and --seed-only", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		logger := logger.New(logger.WithStdout(silence), logger.WithStderr(silence))
+
+		c := cmd.NewNamespaceCopyCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"prod", "hotfix", "--history", "--seed-only"})
+
+		err := c.Execute()
+
+		assert.Error(t, err)
+	})
+	t.Run("--seed-only", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+		bn.Set("prod", "user", "email@domain.tld", 1) // nolint:errcheck
+		bn.Set("prod", "user", "email@domain.tld", 7) // nolint:errcheck
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(stderr))
+
+		c := cmd.NewNamespaceCopyCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"prod", "hotfix", "--seed-only"})
+
+		err := c.Execute()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "7\n", stdout.String())
+		assert.Equal(t, "", stderr.String())
+
+		commits, _ := repo.Commits("refs/build-number/hotfix")
+		assert.Len(t, commits, 1)
+	})
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"github.com/anselstetter/git-build-number/internal/buildnumber"
+	"github.com/anselstetter/git-build-number/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+const synopsisNamespaceCreate = `Create a namespace.
+
+With --from the namespace continues after the current build number of another
+namespace, with --start it starts at the given number. Without either, the
+defaults from the git config are used, falling back to 1:
+
+  [build-number]
+    start = 100
+  [build-number "release/*"]
+    from = prod
+
+The same defaults apply to namespaces created by get --create and inc.`
+
+func NewNamespaceCreateCommand(buildNumber buildnumber.BuildNumber, logger logger.Logger) *cobra.Command {
+	var (
+		user  string
+		email string
+		from  string
+		start int64
+	)
+	cmd := &cobra.Command{
+		Use:    "create <namespace>",
+		Short:  "Create a namespace",
+		Long:   synopsisNamespaceCreate,
+		PreRun: IgnoreAdditonalArgs(logger.StderrWriter(), 2),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return ErrMissingNamespace
+			}
+			if start < 0 {
+				return ErrInvalidNumber
+			}
+			return nil
+		},
+		RunE: SilenceUsageE(func(cmd *cobra.Command, args []string) error {
+			return Create(buildNumber, logger, args[0], user, email, buildnumber.Seed{From: from, Start: start})
+		}),
+	}
+	cmd.Flags().StringVarP(&user, "user", "u", "build number", "the author name")
+	cmd.Flags().StringVarP(&email, "email", "e", "not set", "the author email")
+	cmd.Flags().StringVar(&from, "from", "", "continue after the build number of this namespace")
+	cmd.Flags().Int64Var(&start, "start", 0, "the first build number")
+	cmd.MarkFlagsMutuallyExclusive("from", "start")
+
+	return cmd
+}
+
+func Create(buildNumber buildnumber.BuildNumber, logger logger.Logger, namespace string, user string, email string, seed buildnumber.Seed) error {
+	entry, err := buildNumber.Create(namespace, user, email, seed)
+	if err != nil {
+		return err
+	}
+	logger.Stdoutln(entry.Number)
+	return nil
+}
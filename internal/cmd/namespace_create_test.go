@@ -0,0 +1,95 @@
+package cmd_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/anselstetter/git-build-number/internal/buildnumber"
+	"github.com/anselstetter/git-build-number/internal/cmd"
+	"github.com/anselstetter/git-build-number/internal/logger"
+	"github.com/anselstetter/git-build-number/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamespaceCreate(t *testing.T) {
+	silence := bytes.NewBuffer([]byte{})
+
+	t.Run("without args", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		logger := logger.New(logger.WithStdout(silence), logger.WithStderr(silence))
+
+		c := cmd.NewNamespaceCreateCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+
+		err := c.Execute()
+
+		assert.ErrorIs(t, err, cmd.ErrMissingNamespace)
+	})
+	t.Run("--from and --start", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		logger := logger.New(logger.WithStdout(silence), logger.WithStderr(silence))
+
+		c := cmd.NewNamespaceCreateCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"test", "--from", "prod", "--start", "5"})
+
+		err := c.Execute()
+
+		assert.Error(t, err)
+	})
+	t.Run("--start", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(stderr))
+
+		c := cmd.NewNamespaceCreateCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"test", "--start", "10000"})
+
+		err := c.Execute()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "10000\n", stdout.String())
+		assert.Equal(t, "", stderr.String())
+	})
+	t.Run("--from", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+		bn.Set("prod", "user", "email@domain.tld", 7) // nolint:errcheck
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(stderr))
+
+		c := cmd.NewNamespaceCreateCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"release/1", "--from", "prod"})
+
+		err := c.Execute()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "8\n", stdout.String())
+		assert.Equal(t, "", stderr.String())
+	})
+}
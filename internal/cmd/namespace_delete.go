@@ -1,12 +1,20 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
+	"io"
+
 	"github.com/anselstetter/git-build-number/internal/buildnumber"
 	"github.com/anselstetter/git-build-number/internal/logger"
 	"github.com/spf13/cobra"
 )
 
-func NewNamespaceDeleteCommand(buildNumber buildnumber.BuildNumber, logger logger.Logger) *cobra.Command {
+func NewNamespaceDeleteCommand(buildNumber buildnumber.BuildNumber, logger logger.Logger, reader io.Reader) *cobra.Command {
+	var (
+		remote string
+		yes    bool
+	)
 	cmd := &cobra.Command{
 		Use:   "delete <namespace>...",
 		Short: "Delete a namespace",
@@ -17,12 +25,44 @@ func NewNamespaceDeleteCommand(buildNumber buildnumber.BuildNumber, logger logge
 			return nil
 		},
 		RunE: SilenceUsageE(func(cmd *cobra.Command, args []string) error {
-			return Delete(buildNumber, logger, args...)
+			if remote != "" {
+				return DeleteRemote(cmd.Context(), buildNumber, logger, remote, yes, reader, args...)
+			}
+			return Delete(buildNumber, logger, yes, args...)
 		}),
 	}
+	cmd.Flags().StringVarP(&remote, "remote", "r", "", "delete the namespaces on the remote instead of locally")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "I know what I’m doing")
+
 	return cmd
 }
 
-func Delete(buildNumber buildnumber.BuildNumber, logger logger.Logger, namespaces ...string) error {
+func Delete(buildNumber buildnumber.BuildNumber, logger logger.Logger, yes bool, namespaces ...string) error {
+	protected, err := buildNumber.Protected(namespaces...)
+	if err != nil {
+		return err
+	}
+	if err := guardProtected(protected, yes, namespaces); err != nil {
+		return err
+	}
 	return buildNumber.Delete(namespaces...)
 }
+
+func DeleteRemote(ctx context.Context, buildNumber buildnumber.BuildNumber, logger logger.Logger, remote string, yes bool, reader io.Reader, namespaces ...string) error {
+	protected, err := buildNumber.Protected(namespaces...)
+	if err != nil {
+		return err
+	}
+	protectedRemote, err := buildNumber.ProtectedRemote(ctx, remote, buildnumber.Filter{Include: namespaces})
+	if err != nil {
+		return err
+	}
+	if err := guardProtected(append(protected, protectedRemote...), yes, namespaces); err != nil {
+		return err
+	}
+	msg := fmt.Sprintf("The namespaces will be deleted on remote %s!\nUse --yes to skip the confirmation prompt.\n", remote)
+	if yes || confirm(logger, msg, "Continue?", reader) {
+		return buildNumber.DeleteRemote(ctx, remote, namespaces...)
+	}
+	return nil
+}
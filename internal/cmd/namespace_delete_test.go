@@ -2,6 +2,7 @@ package cmd_test
 
 import (
 	"bytes"
+	"strings"
 	"testing"
 
 	"github.com/anselstetter/git-build-number/internal/buildnumber"
@@ -25,7 +26,7 @@ func TestNamespaceDelete(t *testing.T) {
 
 		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(stderr))
 
-		c := cmd.NewNamespaceDeleteCommand(bn, logger)
+		c := cmd.NewNamespaceDeleteCommand(bn, logger, strings.NewReader(""))
 		c.SetOut(silence)
 		c.SetErr(silence)
 
@@ -46,7 +47,7 @@ func TestNamespaceDelete(t *testing.T) {
 
 		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(stderr))
 
-		c := cmd.NewNamespaceDeleteCommand(bn, logger)
+		c := cmd.NewNamespaceDeleteCommand(bn, logger, strings.NewReader(""))
 		c.SetOut(silence)
 		c.SetErr(silence)
 		c.SetArgs([]string{"invalid"})
@@ -70,7 +71,7 @@ func TestNamespaceDelete(t *testing.T) {
 
 		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(stderr))
 
-		c := cmd.NewNamespaceDeleteCommand(bn, logger)
+		c := cmd.NewNamespaceDeleteCommand(bn, logger, strings.NewReader(""))
 		c.SetOut(silence)
 		c.SetErr(silence)
 		c.SetArgs([]string{"test"})
@@ -85,4 +86,96 @@ func TestNamespaceDelete(t *testing.T) {
 		assert.Equal(t, "", stdout.String())
 		assert.Equal(t, "", stderr.String())
 	})
+	t.Run("with remote no confirmation", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		remote := addRemote(t, "origin", true, repo)
+		bn := buildnumber.New(repo)
+		bnRemote := buildnumber.New(remote)
+		bnRemote.Set("test", "user", "email@domain.tld", 1) // nolint:errcheck
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(stderr))
+
+		c := cmd.NewNamespaceDeleteCommand(bn, logger, strings.NewReader("n\n"))
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"test", "--remote", "origin"})
+
+		err := c.Execute()
+		assert.NoError(t, err)
+
+		ns, err := bnRemote.Get("test", "", "", false)
+		assert.NoError(t, err)
+		assert.NotNil(t, ns)
+
+		assert.True(t, len(stdout.String()) > 0, "should ask for confirmation")
+		assert.Equal(t, "", stderr.String())
+	})
+	t.Run("with remote --yes", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		remote := addRemote(t, "origin", true, repo)
+		bn := buildnumber.New(repo)
+		bn.Set("test", "user", "email@domain.tld", 2) // nolint:errcheck
+		bnRemote := buildnumber.New(remote)
+		bnRemote.Set("test", "user", "email@domain.tld", 1)  // nolint:errcheck
+		bnRemote.Set("other", "user", "email@domain.tld", 4) // nolint:errcheck
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(stderr))
+
+		c := cmd.NewNamespaceDeleteCommand(bn, logger, strings.NewReader(""))
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"test", "--remote", "origin", "--yes"})
+
+		err := c.Execute()
+		assert.NoError(t, err)
+
+		ns, err := bnRemote.Get("test", "", "", false)
+		assert.ErrorIs(t, err, buildnumber.ErrBuildNumberNotFound)
+		assert.Nil(t, ns)
+
+		ns, err = bnRemote.Get("other", "", "", false)
+		assert.NoError(t, err)
+		assert.NotNil(t, ns)
+
+		ns, err = bn.Get("test", "", "", false)
+		assert.NoError(t, err)
+		assert.NotNil(t, ns)
+
+		assert.Equal(t, "", stdout.String())
+		assert.Equal(t, "", stderr.String())
+	})
+	t.Run("with remote protected on the remote", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		remote := addRemote(t, "origin", true, repo)
+		bn := buildnumber.New(repo)
+		bnRemote := buildnumber.New(remote)
+		bnRemote.Set("test", "user", "email@domain.tld", 1)                                             // nolint:errcheck
+		bnRemote.SetMetadata("test", "user", "email@domain.tld", buildnumber.Metadata{Protected: true}) // nolint:errcheck
+
+		logger := logger.New(logger.WithStdout(silence), logger.WithStderr(silence))
+
+		c := cmd.NewNamespaceDeleteCommand(bn, logger, strings.NewReader("y\n"))
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"test", "--remote", "origin"})
+
+		err := c.Execute()
+		assert.ErrorIs(t, err, buildnumber.ErrProtected)
+
+		ns, err := bnRemote.Get("test", "", "", false)
+		assert.NoError(t, err)
+		assert.NotNil(t, ns)
+	})
 }
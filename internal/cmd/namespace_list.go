@@ -1,33 +1,129 @@
 package cmd
 
 import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
 	"github.com/anselstetter/git-build-number/internal/buildnumber"
 	"github.com/anselstetter/git-build-number/internal/logger"
 	"github.com/spf13/cobra"
 )
 
+const synopsisNamespaceList = `List all namespaces with their build number.
+
+The plain format prints the namespace and its build number, followed by the sync state
+with --remote. The table format adds the hash, the time and author of the last update,
+the number of builds and the subject of the numbered commit.
+
+The number of builds is unknown (-) after a shallow fetch.`
+
 func NewNamespaceListCommand(buildNumber buildnumber.BuildNumber, logger logger.Logger) *cobra.Command {
+	var (
+		namespaces []string
+		exclude    []string
+		remote     string
+		sort       string
+		format     string
+	)
 	cmd := &cobra.Command{
 		Use:    "list",
 		Short:  "List all namespaces",
+		Long:   synopsisNamespaceList,
 		PreRun: IgnoreAdditonalArgs(logger.StderrWriter(), 1),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if !slices.Contains([]string{"name", "number", "updated"}, sort) {
+				return fmt.Errorf("%w: %s", ErrUnknownSort, sort)
+			}
+			if !slices.Contains([]string{"plain", "table", "json"}, format) {
+				return fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+			}
+			return nil
+		},
 		RunE: SilenceUsageE(func(cmd *cobra.Command, args []string) error {
-			return ListNamespaces(buildNumber, logger)
+			filter := buildnumber.Filter{Include: namespaces, Exclude: exclude}
+			return ListNamespaces(cmd.Context(), buildNumber, logger, filter, remote, sort, format)
 		}),
 	}
+	cmd.Flags().StringArrayVarP(&namespaces, "namespace", "n", []string{}, "only these namespaces or globs (pr/*, release/**)")
+	cmd.Flags().StringArrayVar(&exclude, "exclude", []string{}, "skip these namespaces or globs")
+	cmd.Flags().StringVarP(&remote, "remote", "r", "", "compare with this remote")
+	cmd.Flags().StringVarP(&sort, "sort", "s", "name", "sort by name, number or updated")
+	cmd.Flags().StringVarP(&format, "format", "f", "plain", "the output format (plain, table, json)")
+
 	return cmd
 }
 
-func ListNamespaces(buildNumber buildnumber.BuildNumber, logger logger.Logger) error {
-	namespaces, err := buildNumber.Namespaces()
+func ListNamespaces(ctx context.Context, buildNumber buildnumber.BuildNumber, logger logger.Logger, filter buildnumber.Filter, remote string, sort string, format string) error {
+	details, err := buildNumber.Details(ctx, filter, remote)
 	if err != nil {
 		return err
 	}
-	out := []any{}
-	for _, ns := range namespaces {
-		out = append(out, ns.Name)
-		out = append(out, ns.Entry.Number)
+	slices.SortStableFunc(details, func(a, b buildnumber.Details) int {
+		switch sort {
+		case "number":
+			return cmp.Compare(b.Number, a.Number)
+		case "updated":
+			return b.Updated.Compare(a.Updated)
+		default:
+			return strings.Compare(a.Name, b.Name)
+		}
+	})
+	if format == "json" {
+		out, err := json.MarshalIndent(details, "", "  ")
+		if err != nil {
+			return err
+		}
+		logger.Stdoutln(string(out))
+		return nil
+	}
+	if format == "plain" {
+		rows := [][]any{}
+		for _, d := range details {
+			row := []any{namespaceName(d), "-"}
+			if d.Hash != "" {
+				row[1] = d.Number
+			}
+			if remote != "" {
+				row = append(row, d.State)
+			}
+			rows = append(rows, row)
+		}
+		logger.StdoutRows(rows...)
+		return nil
 	}
-	logger.StdoutTable(out...)
+	header := []any{"NAMESPACE", "NUMBER", "HASH", "UPDATED", "AUTHOR", "BUILDS", "SUBJECT"}
+	if remote != "" {
+		header = slices.Insert(header, 6, "STATUS")
+	}
+	rows := [][]any{header}
+	for _, d := range details {
+		name := namespaceName(d)
+		if d.Hash == "" {
+			rows = append(rows, []any{name, "-"})
+			continue
+		}
+		builds := any("-")
+		if d.Builds != nil {
+			builds = *d.Builds
+		}
+		row := []any{name, d.Number, d.Hash[:min(7, len(d.Hash))], d.Updated.Format(time.DateTime), d.Author, builds, d.Subject}
+		if remote != "" {
+			row = slices.Insert(row, 6, any(d.State))
+		}
+		rows = append(rows, row)
+	}
+	logger.StdoutRows(rows...)
 	return nil
 }
+
+func namespaceName(d buildnumber.Details) string {
+	if d.Target != "" {
+		return fmt.Sprintf("%s -> %s", d.Name, d.Target)
+	}
+	return d.Name
+}
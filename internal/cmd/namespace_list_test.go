@@ -2,6 +2,7 @@ package cmd_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"testing"
 
 	"github.com/anselstetter/git-build-number/internal/buildnumber"
@@ -34,7 +35,195 @@ func TestNamespaceList(t *testing.T) {
 		err := c.Execute()
 
 		assert.NoError(t, err)
-		assert.Equal(t, "test  1\nother 4\n", stdout.String())
+		assert.Equal(t, "other 4\ntest  1\n", stdout.String())
+		assert.Equal(t, "", stderr.String())
+	})
+	t.Run("table", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+		bn.Set("test", "user", "email@domain.tld", 1)  // nolint:errcheck
+		bn.Set("other", "user", "email@domain.tld", 4) // nolint:errcheck
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(stderr))
+
+		c := cmd.NewNamespaceListCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"--format", "table"})
+
+		err := c.Execute()
+
+		assert.NoError(t, err)
+		assert.Regexp(t, "^NAMESPACE NUMBER HASH    UPDATED             AUTHOR BUILDS SUBJECT\n"+
+			"other     4      [0-9a-f]{7} [0-9-]{10} [0-9:]{8} user   1      Initial commit\n"+
+			"test      1      [0-9a-f]{7} [0-9-]{10} [0-9:]{8} user   1      Initial commit\n$", stdout.String())
+		assert.Equal(t, "", stderr.String())
+	})
+	t.Run("table after a shallow fetch", func(t *testing.T) {
+		t.Parallel()
+
+		repo := fetchShallow(t, "test", 3)
+		bn := buildnumber.New(repo)
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(stderr))
+
+		c := cmd.NewNamespaceListCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"--format", "table"})
+
+		err := c.Execute()
+
+		assert.NoError(t, err)
+		assert.Regexp(t, "^NAMESPACE NUMBER HASH    UPDATED             AUTHOR BUILDS SUBJECT\n"+
+			"test      4      [0-9a-f]{7} [0-9-]{10} [0-9:]{8} user   -      Initial commit\n$", stdout.String())
+		assert.Equal(t, "", stderr.String())
+	})
+	t.Run("invalid sort", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		logger := logger.New(logger.WithStdout(silence), logger.WithStderr(silence))
+
+		c := cmd.NewNamespaceListCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"--sort", "invalid"})
+
+		err := c.Execute()
+
+		assert.ErrorIs(t, err, cmd.ErrUnknownSort)
+	})
+	t.Run("invalid format", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		logger := logger.New(logger.WithStdout(silence), logger.WithStderr(silence))
+
+		c := cmd.NewNamespaceListCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"--format", "invalid"})
+
+		err := c.Execute()
+
+		assert.ErrorIs(t, err, cmd.ErrUnknownFormat)
+	})
+	t.Run("json sorted by number with filter", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+		bn.Set("env/dev", "user", "email@domain.tld", 1)  // nolint:errcheck
+		bn.Set("env/dev", "user", "email@domain.tld", 2)  // nolint:errcheck
+		bn.Set("env/prod", "user", "email@domain.tld", 7) // nolint:errcheck
+		bn.Set("pr/1", "user", "email@domain.tld", 9)     // nolint:errcheck
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(stderr))
+
+		c := cmd.NewNamespaceListCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"--format", "json", "--sort", "number", "--namespace", "env/*"})
+
+		err := c.Execute()
+		assert.NoError(t, err)
+
+		details := []buildnumber.Details{}
+		err = json.Unmarshal(stdout.Bytes(), &details)
+		assert.NoError(t, err)
+
+		assert.Len(t, details, 2)
+		assert.Equal(t, "env/prod", details[0].Name)
+		assert.Equal(t, int64(7), details[0].Number)
+		assert.Equal(t, "env/dev", details[1].Name)
+		assert.Equal(t, 2, *details[1].Builds)
+		assert.Equal(t, "Initial commit", details[1].Subject)
+		assert.Equal(t, "", stderr.String())
+	})
+	t.Run("with remote", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		_ = addRemote(t, "origin", true, repo)
+		bn := buildnumber.New(repo)
+		bn.Set("test", "user", "email@domain.tld", 1) // nolint:errcheck
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(stderr))
+
+		c := cmd.NewNamespaceListCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"--remote", "origin", "--format", "table"})
+
+		err := c.Execute()
+
+		assert.NoError(t, err)
+		assert.Regexp(t, "^NAMESPACE NUMBER HASH    UPDATED             AUTHOR BUILDS STATUS     SUBJECT\n"+
+			"test      1      [0-9a-f]{7} [0-9-]{10} [0-9:]{8} user   1      local only Initial commit\n$", stdout.String())
+		assert.Equal(t, "", stderr.String())
+	})
+	t.Run("plain with remote", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		_ = addRemote(t, "origin", true, repo)
+		bn := buildnumber.New(repo)
+		bn.Set("test", "user", "email@domain.tld", 1) // nolint:errcheck
+
+		stdout := bytes.NewBuffer([]byte{})
+
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(silence))
+
+		c := cmd.NewNamespaceListCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"--remote", "origin"})
+
+		err := c.Execute()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "test 1 local only\n", stdout.String())
+	})
+	t.Run("with alias", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+		bn.Set("release/1", "user", "email@domain.tld", 4) // nolint:errcheck
+		bn.Alias("current", "release/1")                   // nolint:errcheck
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(stderr))
+
+		c := cmd.NewNamespaceListCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+
+		err := c.Execute()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "current -> release/1 4\nrelease/1            4\n", stdout.String())
 		assert.Equal(t, "", stderr.String())
 	})
 }
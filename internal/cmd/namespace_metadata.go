@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"github.com/anselstetter/git-build-number/internal/buildnumber"
+	"github.com/anselstetter/git-build-number/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+const synopsisNamespaceMetadata = `Show or change the metadata of a namespace.
+
+Without flags the metadata is printed. The metadata is stored next to the build number
+and travels with push and fetch.
+
+  protected  delete, clear and mirror require --yes and the explicit namespace name,
+             rename is refused
+  frozen     inc, set and exec are refused`
+
+func NewNamespaceMetadataCommand(buildNumber buildnumber.BuildNumber, logger logger.Logger) *cobra.Command {
+	var (
+		user        string
+		email       string
+		description string
+		owner       string
+		protected   bool
+		frozen      bool
+	)
+	cmd := &cobra.Command{
+		Use:    "metadata <namespace>",
+		Short:  "Show or change the metadata of a namespace",
+		Long:   synopsisNamespaceMetadata,
+		PreRun: IgnoreAdditonalArgs(logger.StderrWriter(), 2),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return ErrMissingNamespace
+			}
+			return nil
+		},
+		RunE: SilenceUsageE(func(cmd *cobra.Command, args []string) error {
+			metadata, err := buildNumber.Metadata(args[0])
+			if err != nil {
+				return err
+			}
+			flags := cmd.Flags()
+			if flags.Changed("description") {
+				metadata.Description = description
+			}
+			if flags.Changed("owner") {
+				metadata.Owner = owner
+			}
+			if flags.Changed("protected") {
+				metadata.Protected = protected
+			}
+			if flags.Changed("frozen") {
+				metadata.Frozen = frozen
+			}
+			changed := flags.Changed("description") || flags.Changed("owner") || flags.Changed("protected") || flags.Changed("frozen")
+			if changed {
+				return SetMetadata(buildNumber, logger, args[0], user, email, *metadata)
+			}
+			return ShowMetadata(buildNumber, logger, args[0])
+		}),
+	}
+	cmd.Flags().StringVarP(&user, "user", "u", "build number", "the author name")
+	cmd.Flags().StringVarP(&email, "email", "e", "not set", "the author email")
+	cmd.Flags().StringVar(&description, "description", "", "the description")
+	cmd.Flags().StringVar(&owner, "owner", "", "the owner")
+	cmd.Flags().BoolVar(&protected, "protected", false, "protect the namespace from deletion")
+	cmd.Flags().BoolVar(&frozen, "frozen", false, "refuse new build numbers")
+
+	return cmd
+}
+
+func SetMetadata(buildNumber buildnumber.BuildNumber, logger logger.Logger, namespace string, user string, email string, metadata buildnumber.Metadata) error {
+	if err := buildNumber.SetMetadata(namespace, user, email, metadata); err != nil {
+		return err
+	}
+	return ShowMetadata(buildNumber, logger, namespace)
+}
+
+func ShowMetadata(buildNumber buildnumber.BuildNumber, logger logger.Logger, namespace string) error {
+	if _, err := buildNumber.Get(namespace, "", "", false); err != nil {
+		return err
+	}
+	metadata, err := buildNumber.Metadata(namespace)
+	if err != nil {
+		return err
+	}
+	logger.StdoutTable(
+		"description", metadata.Description,
+		"owner", metadata.Owner,
+		"protected", metadata.Protected,
+		"frozen", metadata.Frozen,
+	)
+	return nil
+}
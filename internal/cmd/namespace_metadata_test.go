@@ -0,0 +1,183 @@
+package cmd_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/anselstetter/git-build-number/internal/buildnumber"
+	"github.com/anselstetter/git-build-number/internal/cmd"
+	"github.com/anselstetter/git-build-number/internal/logger"
+	"github.com/anselstetter/git-build-number/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamespaceMetadata(t *testing.T) {
+	silence := bytes.NewBuffer([]byte{})
+
+	t.Run("without args", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		logger := logger.New(logger.WithStdout(silence), logger.WithStderr(silence))
+
+		c := cmd.NewNamespaceMetadataCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+
+		err := c.Execute()
+
+		assert.ErrorIs(t, err, cmd.ErrMissingNamespace)
+	})
+	t.Run("missing namespace", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		logger := logger.New(logger.WithStdout(silence), logger.WithStderr(silence))
+
+		c := cmd.NewNamespaceMetadataCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"test"})
+
+		err := c.Execute()
+
+		assert.ErrorIs(t, err, buildnumber.ErrBuildNumberNotFound)
+	})
+	t.Run("set", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+		bn.Set("test", "user", "email@domain.tld", 1)                                                         // nolint:errcheck
+		bn.SetMetadata("test", "user", "email@domain.tld", buildnumber.Metadata{Description: "release line"}) // nolint:errcheck
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(stderr))
+
+		c := cmd.NewNamespaceMetadataCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"test", "--owner", "team", "--frozen"})
+
+		err := c.Execute()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "description release line\nowner       team\nprotected   false\nfrozen      true\n", stdout.String())
+		assert.Equal(t, "", stderr.String())
+
+		metadata, _ := bn.Metadata("test")
+		assert.Equal(t, &buildnumber.Metadata{Description: "release line", Owner: "team", Frozen: true}, metadata)
+	})
+}
+
+func TestProtectedNamespaces(t *testing.T) {
+	silence := bytes.NewBuffer([]byte{})
+
+	setup := func(t *testing.T) buildnumber.BuildNumber {
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+		bn.Set("prod", "user", "email@domain.tld", 1)                                             // nolint:errcheck
+		bn.Set("dev", "user", "email@domain.tld", 1)                                              // nolint:errcheck
+		bn.SetMetadata("prod", "user", "email@domain.tld", buildnumber.Metadata{Protected: true}) // nolint:errcheck
+		return bn
+	}
+
+	t.Run("delete without --yes", func(t *testing.T) {
+		t.Parallel()
+
+		bn := setup(t)
+		logger := logger.New(logger.WithStdout(silence), logger.WithStderr(silence))
+
+		c := cmd.NewNamespaceDeleteCommand(bn, logger, strings.NewReader(""))
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"prod"})
+
+		err := c.Execute()
+		assert.ErrorIs(t, err, buildnumber.ErrProtected)
+
+		_, err = bn.Get("prod", "", "", false)
+		assert.NoError(t, err)
+	})
+	t.Run("delete with --yes", func(t *testing.T) {
+		t.Parallel()
+
+		bn := setup(t)
+		logger := logger.New(logger.WithStdout(silence), logger.WithStderr(silence))
+
+		c := cmd.NewNamespaceDeleteCommand(bn, logger, strings.NewReader(""))
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"prod", "--yes"})
+
+		err := c.Execute()
+		assert.NoError(t, err)
+
+		_, err = bn.Get("prod", "", "", false)
+		assert.ErrorIs(t, err, buildnumber.ErrBuildNumberNotFound)
+	})
+	t.Run("clear without namespace name", func(t *testing.T) {
+		t.Parallel()
+
+		bn := setup(t)
+		logger := logger.New(logger.WithStdout(silence), logger.WithStderr(silence))
+
+		c := cmd.NewNamespaceClearCommand(bn, logger, strings.NewReader(""))
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"--yes"})
+
+		err := c.Execute()
+		assert.ErrorIs(t, err, buildnumber.ErrProtected)
+
+		ns, _ := bn.Namespaces()
+		assert.Len(t, ns, 2)
+	})
+	t.Run("clear with namespace name", func(t *testing.T) {
+		t.Parallel()
+
+		bn := setup(t)
+		logger := logger.New(logger.WithStdout(silence), logger.WithStderr(silence))
+
+		c := cmd.NewNamespaceClearCommand(bn, logger, strings.NewReader(""))
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"--yes", "--protected", "prod"})
+
+		err := c.Execute()
+		assert.NoError(t, err)
+
+		ns, _ := bn.Namespaces()
+		assert.Empty(t, ns)
+	})
+	t.Run("mirror", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		remote := addRemote(t, "origin", true, repo)
+		bn := buildnumber.New(repo)
+		bnRemote := buildnumber.New(remote)
+		bnRemote.Set("prod", "user", "email@domain.tld", 1)                                             // nolint:errcheck
+		bnRemote.SetMetadata("prod", "user", "email@domain.tld", buildnumber.Metadata{Protected: true}) // nolint:errcheck
+
+		logger := logger.New(logger.WithStdout(silence), logger.WithStderr(silence))
+
+		c := cmd.NewNamespaceMirrorCommand(bn, logger, strings.NewReader(""))
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"--yes"})
+
+		err := c.Execute()
+		assert.ErrorIs(t, err, buildnumber.ErrProtected)
+
+		_, err = bnRemote.Get("prod", "", "", false)
+		assert.NoError(t, err)
+	})
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"io"
 
 	"github.com/anselstetter/git-build-number/internal/buildnumber"
@@ -10,26 +11,35 @@ import (
 
 func NewNamespaceMirrorCommand(buildNumber buildnumber.BuildNumber, logger logger.Logger, reader io.Reader) *cobra.Command {
 	var (
-		remote string
-		yes    bool
+		remote    string
+		yes       bool
+		protected []string
 	)
 	cmd := &cobra.Command{
 		Use:    "mirror",
 		Short:  "Mirror all local namespaces",
 		PreRun: IgnoreAdditonalArgs(logger.StderrWriter(), 1),
 		RunE: SilenceUsageE(func(cmd *cobra.Command, args []string) error {
-			return MirrorNamespaces(buildNumber, logger, remote, yes, reader)
+			return MirrorNamespaces(cmd.Context(), buildNumber, logger, remote, yes, protected, reader)
 		}),
 	}
 	cmd.Flags().StringVarP(&remote, "remote", "r", "origin", "the remote")
 	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "I know what I’m doing")
+	cmd.Flags().StringArrayVar(&protected, "protected", []string{}, "also delete this protected remote namespace (requires --yes)")
 
 	return cmd
 }
 
-func MirrorNamespaces(buildNumber buildnumber.BuildNumber, logger logger.Logger, remote string, yes bool, reader io.Reader) error {
+func MirrorNamespaces(ctx context.Context, buildNumber buildnumber.BuildNumber, logger logger.Logger, remote string, yes bool, explicit []string, reader io.Reader) error {
 	if yes || confirm(logger, "All remote namespaces that are not present locally will be deleted!\nUse --yes to skip the confirmation prompt.\n", "Continue?", reader) {
-		return buildNumber.Mirror(remote)
+		protected, err := buildNumber.ProtectedRemote(ctx, remote, buildnumber.Filter{}, buildnumber.StateRemoteOnly)
+		if err != nil {
+			return err
+		}
+		if err := guardProtected(protected, yes, explicit); err != nil {
+			return err
+		}
+		return buildNumber.Mirror(ctx, remote)
 	}
 	return nil
 }
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/anselstetter/git-build-number/internal/buildnumber"
+	"github.com/anselstetter/git-build-number/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+const synopsisNamespacePrune = `Delete stale namespaces, e.g. the ones of merged pull requests.
+
+Namespaces are selected by glob (--namespace, --exclude), by the age of their
+last build number (--older-than 30d), by whether the branch of the same name
+is gone (--gone) or by keeping only the most recent ones (--max). All given
+criteria have to match. Protected namespaces are never pruned.
+
+--gone checks the branches of --remote if given, the local branches otherwise.
+With --remote the namespaces are deleted on the remote as well.
+
+The selected namespaces are listed before anything is deleted.`
+
+func NewNamespacePruneCommand(buildNumber buildnumber.BuildNumber, logger logger.Logger, reader io.Reader) *cobra.Command {
+	var (
+		namespaces []string
+		exclude    []string
+		olderThan  string
+		gone       bool
+		maxCount   int
+		remote     string
+		yes        bool
+	)
+	cmd := &cobra.Command{
+		Use:    "prune",
+		Short:  "Delete stale namespaces",
+		Long:   synopsisNamespacePrune,
+		PreRun: IgnoreAdditonalArgs(logger.StderrWriter(), 1),
+		RunE: SilenceUsageE(func(cmd *cobra.Command, args []string) error {
+			options := buildnumber.PruneOptions{
+				Filter: buildnumber.Filter{Include: namespaces, Exclude: exclude},
+				Gone:   gone,
+				Remote: remote,
+				Max:    maxCount,
+			}
+			if olderThan != "" {
+				age, err := buildnumber.ParseAge(olderThan)
+				if err != nil {
+					return err
+				}
+				options.OlderThan = age
+			}
+			return Prune(cmd.Context(), buildNumber, logger, options, yes, reader)
+		}),
+	}
+	cmd.Flags().StringArrayVarP(&namespaces, "namespace", "n", []string{}, "only these namespaces or globs (pr/*, release/**)")
+	cmd.Flags().StringArrayVar(&exclude, "exclude", []string{}, "skip these namespaces or globs")
+	cmd.Flags().StringVar(&olderThan, "older-than", "", "only namespaces without a build number for this long (30d, 2w, 12h)")
+	cmd.Flags().BoolVar(&gone, "gone", false, "only namespaces whose branch no longer exists")
+	cmd.Flags().IntVar(&maxCount, "max", 0, "keep this many of the most recent namespaces")
+	cmd.Flags().StringVarP(&remote, "remote", "r", "", "delete the namespaces on this remote as well")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "I know what I’m doing")
+
+	return cmd
+}
+
+func Prune(ctx context.Context, buildNumber buildnumber.BuildNumber, logger logger.Logger, options buildnumber.PruneOptions, yes bool, reader io.Reader) error {
+	stale, err := buildNumber.Stale(ctx, options, time.Now())
+	if err != nil {
+		return err
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+	rows := [][]any{}
+	names := []string{}
+	for _, s := range stale {
+		rows = append(rows, []any{s.Name, s.Entry.Number, s.When.Format(time.DateOnly)})
+		names = append(names, s.Name)
+	}
+	logger.StdoutRows(rows...)
+
+	if yes || confirm(logger, "\nThe namespaces above will be deleted!\nUse --yes to skip the confirmation prompt.\n", "Continue?", reader) {
+		return buildNumber.Prune(ctx, options.Remote, names...)
+	}
+	return nil
+}
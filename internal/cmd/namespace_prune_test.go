@@ -0,0 +1,138 @@
+package cmd_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/anselstetter/git-build-number/internal/buildnumber"
+	"github.com/anselstetter/git-build-number/internal/cmd"
+	"github.com/anselstetter/git-build-number/internal/logger"
+	"github.com/anselstetter/git-build-number/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamespacePrune(t *testing.T) {
+	silence := bytes.NewBuffer([]byte{})
+
+	t.Run("without criteria", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		logger := logger.New(logger.WithStdout(silence), logger.WithStderr(silence))
+
+		c := cmd.NewNamespacePruneCommand(bn, logger, strings.NewReader(""))
+		c.SetOut(silence)
+		c.SetErr(silence)
+
+		err := c.Execute()
+
+		assert.ErrorIs(t, err, buildnumber.ErrMissingCriteria)
+	})
+	t.Run("invalid age", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		logger := logger.New(logger.WithStdout(silence), logger.WithStderr(silence))
+
+		c := cmd.NewNamespacePruneCommand(bn, logger, strings.NewReader(""))
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"--older-than", "a month"})
+
+		err := c.Execute()
+
+		assert.ErrorIs(t, err, buildnumber.ErrInvalidAge)
+	})
+	t.Run("no confirmation", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+		bn.Set("pr/1", "user", "email@domain.tld", 1) // nolint:errcheck
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(stderr))
+
+		c := cmd.NewNamespacePruneCommand(bn, logger, strings.NewReader("n\n"))
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"--namespace", "pr/*"})
+
+		err := c.Execute()
+		assert.NoError(t, err)
+
+		ns, err := bn.Get("pr/1", "", "", false)
+		assert.NoError(t, err)
+		assert.NotNil(t, ns)
+
+		assert.True(t, strings.HasPrefix(stdout.String(), "pr/1 1 "), "should list the namespaces")
+		assert.Equal(t, "", stderr.String())
+	})
+	t.Run("--gone --remote --yes", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		remote := addRemote(t, "origin", true, repo)
+		bn := buildnumber.New(repo)
+		bn.Set("feature", "user", "email@domain.tld", 1)     // nolint:errcheck
+		bn.Set("main", "user", "email@domain.tld", 2)        // nolint:errcheck
+		bn.Push(t.Context(), "origin", buildnumber.Filter{}) // nolint:errcheck
+		bnRemote := buildnumber.New(remote)
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(stderr))
+
+		c := cmd.NewNamespacePruneCommand(bn, logger, strings.NewReader(""))
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"--gone", "--remote", "origin", "--yes"})
+
+		err := c.Execute()
+		assert.NoError(t, err)
+
+		for _, b := range []buildnumber.BuildNumber{bn, bnRemote} {
+			_, err = b.Get("feature", "", "", false)
+			assert.ErrorIs(t, err, buildnumber.ErrBuildNumberNotFound)
+
+			entry, err := b.Get("main", "", "", false)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(2), entry.Number)
+		}
+		assert.True(t, strings.HasPrefix(stdout.String(), "feature 1 "), "should list the namespaces")
+		assert.Equal(t, "", stderr.String())
+	})
+	t.Run("after a shallow fetch", func(t *testing.T) {
+		t.Parallel()
+
+		repo := fetchShallow(t, "pr/1", 3)
+		bn := buildnumber.New(repo)
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(stderr))
+
+		c := cmd.NewNamespacePruneCommand(bn, logger, strings.NewReader(""))
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"--namespace", "pr/*", "--yes"})
+
+		err := c.Execute()
+
+		assert.NoError(t, err)
+
+		_, err = bn.Get("pr/1", "", "", false)
+		assert.ErrorIs(t, err, buildnumber.ErrBuildNumberNotFound)
+		assert.True(t, strings.HasPrefix(stdout.String(), "pr/1 4 "), "should list the namespaces")
+		assert.Equal(t, "", stderr.String())
+	})
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/anselstetter/git-build-number/internal/buildnumber"
+	"github.com/anselstetter/git-build-number/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+const synopsisNamespaceRename = `Rename a namespace, keeping its counter and history.
+
+A commit noting the old namespace is recorded and aliases of the old namespace are moved
+to the new one. With --remote the new namespace is pushed first, the old one is only
+deleted once that succeeded. Existing namespaces on the remote are never replaced.`
+
+func NewNamespaceRenameCommand(buildNumber buildnumber.BuildNumber, logger logger.Logger) *cobra.Command {
+	var (
+		user   string
+		email  string
+		remote string
+	)
+	cmd := &cobra.Command{
+		Use:   "rename <old> <new>",
+		Short: "Rename a namespace",
+		Long:  synopsisNamespaceRename,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 2 {
+				return ErrMissingNamespace
+			}
+			return nil
+		},
+		RunE: SilenceUsageE(func(cmd *cobra.Command, args []string) error {
+			return Rename(cmd.Context(), buildNumber, logger, args[0], args[1], user, email, remote)
+		}),
+	}
+	cmd.Flags().StringVarP(&user, "user", "u", "build number", "the author name")
+	cmd.Flags().StringVarP(&email, "email", "e", "not set", "the author email")
+	cmd.Flags().StringVarP(&remote, "remote", "r", "", "rename the namespace on this remote as well")
+
+	return cmd
+}
+
+func Rename(ctx context.Context, buildNumber buildnumber.BuildNumber, logger logger.Logger, from string, to string, user string, email string, remote string) error {
+	entry, err := buildNumber.Rename(ctx, from, to, user, email, remote)
+	if err != nil {
+		return err
+	}
+	logger.Stdoutln(entry.Number)
+	return nil
+}
@@ -0,0 +1,78 @@
+package cmd_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/anselstetter/git-build-number/internal/buildnumber"
+	"github.com/anselstetter/git-build-number/internal/cmd"
+	"github.com/anselstetter/git-build-number/internal/logger"
+	"github.com/anselstetter/git-build-number/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamespaceRename(t *testing.T) {
+	silence := bytes.NewBuffer([]byte{})
+
+	t.Run("without args", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		logger := logger.New(logger.WithStdout(silence), logger.WithStderr(silence))
+
+		c := cmd.NewNamespaceRenameCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+
+		err := c.Execute()
+
+		assert.ErrorIs(t, err, cmd.ErrMissingNamespace)
+	})
+	t.Run("existing namespace", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+		bn.Set("staging", "user", "email@domain.tld", 1) // nolint:errcheck
+		bn.Set("preprod", "user", "email@domain.tld", 2) // nolint:errcheck
+
+		logger := logger.New(logger.WithStdout(silence), logger.WithStderr(silence))
+
+		c := cmd.NewNamespaceRenameCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"staging", "preprod"})
+
+		err := c.Execute()
+
+		assert.ErrorIs(t, err, buildnumber.ErrNamespaceExists)
+	})
+	t.Run("with valid namespace", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+		bn.Set("staging", "user", "email@domain.tld", 3) // nolint:errcheck
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(stderr))
+
+		c := cmd.NewNamespaceRenameCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"staging", "preprod"})
+
+		err := c.Execute()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "3\n", stdout.String())
+		assert.Equal(t, "", stderr.String())
+
+		_, err = bn.Get("staging", "", "", false)
+		assert.ErrorIs(t, err, buildnumber.ErrBuildNumberNotFound)
+	})
+}
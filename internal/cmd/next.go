@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/anselstetter/git-build-number/internal/buildnumber"
+	"github.com/anselstetter/git-build-number/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+func NewNextCommand(buildNumber buildnumber.BuildNumber, logger logger.Logger) *cobra.Command {
+	var (
+		namespace string
+		remote    string
+		force     bool
+		rev       string
+	)
+	cmd := &cobra.Command{
+		Use:    "next",
+		Short:  "Show the build number inc would return",
+		PreRun: IgnoreAdditonalArgs(logger.StderrWriter(), 1),
+		RunE: SilenceUsageE(func(cmd *cobra.Command, args []string) error {
+			if remote == "" && RemoteURL(cmd) != "" {
+				remote = RemoteURLName
+			}
+			return Next(cmd.Context(), buildNumber.At(rev), logger, namespace, remote, force)
+		}),
+	}
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "the namespace")
+	cmd.Flags().StringVarP(&remote, "remote", "r", "", "consult the build number of this remote")
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "force")
+	cmd.Flags().StringVar(&rev, "rev", "", "number this commit instead of HEAD")
+	return cmd
+}
+
+func Next(ctx context.Context, buildNumber buildnumber.BuildNumber, logger logger.Logger, namespace string, remote string, force bool) error {
+	var (
+		entry *buildnumber.Entry
+		err   error
+	)
+	if remote != "" {
+		// The remote is fetched into storage that is thrown away, so next
+		// never changes the repository.
+		if err := buildNumber.ReadOnly(); err != nil {
+			return err
+		}
+		entry, _, err = buildNumber.NextRemote(ctx, namespace, remote, force)
+	} else {
+		entry, _, err = buildNumber.Next(namespace, force)
+	}
+	if err != nil {
+		return err
+	}
+	logger.Stdoutln(entry.Number)
+	return nil
+}
@@ -0,0 +1,132 @@
+package cmd_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/anselstetter/git-build-number/internal/buildnumber"
+	"github.com/anselstetter/git-build-number/internal/cmd"
+	"github.com/anselstetter/git-build-number/internal/logger"
+	"github.com/anselstetter/git-build-number/internal/repository"
+	"github.com/anselstetter/git-build-number/internal/repository/repositorytest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNext(t *testing.T) {
+	silence := bytes.NewBuffer([]byte{})
+
+	t.Run("without flags", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(stderr))
+
+		c := cmd.NewNextCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+
+		err := c.Execute()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "1\n", stdout.String())
+		assert.Equal(t, "", stderr.String())
+
+		_, err = bn.Get("default", "", "", false)
+		assert.ErrorIs(t, err, buildnumber.ErrBuildNumberNotFound)
+	})
+	t.Run("--force", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+		bn.Set("test", "user", "email@domain.tld", 41) // nolint:errcheck
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(stderr))
+
+		c := cmd.NewNextCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"--namespace", "test", "--force"})
+
+		err := c.Execute()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "42\n", stdout.String())
+		assert.Equal(t, "", stderr.String())
+	})
+	t.Run("--remote without remote", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		logger := logger.New(logger.WithStdout(silence), logger.WithStderr(silence))
+
+		c := cmd.NewNextCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"--remote", "origin"})
+
+		err := c.Execute()
+
+		assert.ErrorIs(t, err, repository.ErrRemoteNotFound)
+	})
+	t.Run("--remote is read-only", func(t *testing.T) {
+		t.Parallel()
+
+		remote, path := repositorytest.NewRemote(t, true)
+		origin := buildnumber.New(remote)
+		origin.Set("default", "user", "email@domain.tld", 41) // nolint:errcheck
+
+		repo, local := repositorytest.NewRemote(t, true)
+		_ = repo.AddRemote("origin", path)
+		bn := buildnumber.New(repo)
+		refs, _ := repo.Refs()
+
+		stdout := bytes.NewBuffer([]byte{})
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(silence))
+
+		c := cmd.NewNextCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"--remote", "origin", "--force"})
+
+		err := c.Execute()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "42\n", stdout.String())
+
+		reopened, _ := repository.NewGitDirRepository(local)
+		after, _ := reopened.Refs()
+		assert.Equal(t, refs, after)
+	})
+	t.Run("--rev", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+		bn.Set("default", "user", "email@domain.tld", 123) // nolint:errcheck
+		_, _ = repo.Commit("refs/heads/main", "next", []byte("next"), "Next commit", repository.WithHead())
+
+		stdout := bytes.NewBuffer([]byte{})
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(silence))
+
+		c := cmd.NewNextCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"--rev", "HEAD~1"})
+
+		err := c.Execute()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "123\n", stdout.String())
+	})
+}
@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"context"
+
 	"github.com/anselstetter/git-build-number/internal/buildnumber"
 	"github.com/anselstetter/git-build-number/internal/logger"
 	"github.com/spf13/cobra"
@@ -8,23 +10,28 @@ import (
 
 func NewPushCommand(buildNumber buildnumber.BuildNumber, logger logger.Logger) *cobra.Command {
 	var (
-		remote string
+		remote     string
+		namespaces []string
+		exclude    []string
 	)
 	cmd := &cobra.Command{
 		Use:    "push",
 		Short:  "Push build number(s)",
 		PreRun: IgnoreAdditonalArgs(logger.StderrWriter(), 1),
 		RunE: SilenceUsageE(func(cmd *cobra.Command, args []string) error {
-			return Push(buildNumber, logger, remote)
+			filter := buildnumber.Filter{Include: namespaces, Exclude: exclude}
+			return Push(cmd.Context(), buildNumber, logger, remote, filter)
 		}),
 	}
 	cmd.Flags().StringVarP(&remote, "remote", "r", "origin", "the remote")
+	cmd.Flags().StringArrayVarP(&namespaces, "namespace", "n", []string{}, "only these namespaces or globs (pr/*, release/**)")
+	cmd.Flags().StringArrayVar(&exclude, "exclude", []string{}, "skip these namespaces or globs")
 
 	return cmd
 }
 
-func Push(buildNumber buildnumber.BuildNumber, logger logger.Logger, remote string) error {
-	err := buildNumber.Push(remote)
+func Push(ctx context.Context, buildNumber buildnumber.BuildNumber, logger logger.Logger, remote string, filter buildnumber.Filter) error {
+	err := buildNumber.Push(ctx, remote, filter)
 	if err != nil {
 		return err
 	}
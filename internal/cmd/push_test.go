@@ -37,4 +37,37 @@ func TestPush(t *testing.T) {
 		assert.Equal(t, "", stdout.String())
 		assert.Equal(t, "", stderr.String())
 	})
+	t.Run("--namespace, --exclude", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		remote := addRemote(t, "origin", false, repo)
+		bn := buildnumber.New(repo)
+		bn.Set("pr/1", "user", "email@domain.tld", 1) // nolint:errcheck
+		bn.Set("pr/2", "user", "email@domain.tld", 2) // nolint:errcheck
+		bn.Set("prod", "user", "email@domain.tld", 3) // nolint:errcheck
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(stderr))
+
+		c := cmd.NewPushCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"--namespace", "pr/*", "--namespace", "prod", "--exclude", "pr/2"})
+
+		err := c.Execute()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "", stdout.String())
+		assert.Equal(t, "", stderr.String())
+
+		refs, _ := remote.Refs(repository.WithPrefix("refs/build-number/"))
+		paths := []string{}
+		for _, ref := range refs {
+			paths = append(paths, ref.Path)
+		}
+		assert.ElementsMatch(t, []string{"refs/build-number/pr/1", "refs/build-number/prod"}, paths)
+	})
 }
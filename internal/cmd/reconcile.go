@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/anselstetter/git-build-number/internal/buildnumber"
+	"github.com/anselstetter/git-build-number/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+const synopsisReconcile = `Reconcile a namespace whose local and remote histories diverged.
+
+Strategies:
+  remote     keep the remote history and renumber local entries on top
+  highest    keep the history with the highest number and renumber the other on top
+  timestamp  interleave both histories by time and renumber them after the common base
+
+The result is a linear local history, push it afterwards to update the remote.
+Build numbers that were handed out on both sides are reported.`
+
+func NewReconcileCommand(buildNumber buildnumber.BuildNumber, logger logger.Logger) *cobra.Command {
+	var (
+		namespace string
+		remote    string
+		strategy  string
+	)
+	cmd := &cobra.Command{
+		Use:    "reconcile",
+		Short:  "Reconcile diverged build numbers",
+		Long:   synopsisReconcile,
+		PreRun: IgnoreAdditonalArgs(logger.StderrWriter(), 1),
+		Args: func(cmd *cobra.Command, args []string) error {
+			_, err := buildnumber.ParseStrategy(strategy)
+			return err
+		},
+		RunE: SilenceUsageE(func(cmd *cobra.Command, args []string) error {
+			strategy, _ := buildnumber.ParseStrategy(strategy)
+			return Reconcile(cmd.Context(), buildNumber, logger, namespace, remote, strategy)
+		}),
+	}
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "the namespace")
+	cmd.Flags().StringVarP(&remote, "remote", "r", "origin", "the remote")
+	cmd.Flags().StringVarP(&strategy, "strategy", "s", string(buildnumber.StrategyRemote), "the strategy (remote, highest, timestamp)")
+	return cmd
+}
+
+func Reconcile(ctx context.Context, buildNumber buildnumber.BuildNumber, logger logger.Logger, namespace string, remote string, strategy buildnumber.Strategy) error {
+	result, err := buildNumber.Reconcile(ctx, namespace, remote, strategy)
+	if err != nil {
+		return err
+	}
+	for _, duplicate := range result.Duplicates {
+		logger.Stderrf("build number %d was handed out twice (local %s, remote %s)\n", duplicate.Number, duplicate.Local, duplicate.Remote)
+	}
+	for _, renumbered := range result.Renumbered {
+		logger.Stderrf("renumbered %s %d -> %d (%s)\n", renumbered.Side, renumbered.Old, renumbered.New, renumbered.Entry.Hash)
+	}
+	logger.Stdoutln(result.Entry.Number)
+	return nil
+}
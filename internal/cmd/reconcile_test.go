@@ -0,0 +1,84 @@
+package cmd_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/anselstetter/git-build-number/internal/buildnumber"
+	"github.com/anselstetter/git-build-number/internal/cmd"
+	"github.com/anselstetter/git-build-number/internal/logger"
+	"github.com/anselstetter/git-build-number/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReconcile(t *testing.T) {
+	silence := bytes.NewBuffer([]byte{})
+
+	t.Run("invalid strategy", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(stderr))
+
+		c := cmd.NewReconcileCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"--strategy", "invalid"})
+
+		err := c.Execute()
+
+		assert.ErrorIs(t, err, buildnumber.ErrUnknownStrategy)
+		assert.Equal(t, "", stdout.String())
+		assert.Equal(t, "", stderr.String())
+	})
+	t.Run("without remote", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		logger := logger.New(logger.WithStdout(silence), logger.WithStderr(silence))
+
+		c := cmd.NewReconcileCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+
+		err := c.Execute()
+
+		assert.ErrorIs(t, err, repository.ErrRemoteNotFound)
+	})
+	t.Run("diverged", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		remote := addRemote(t, "origin", true, repo)
+		bnRemote := buildnumber.New(remote)
+		bnRemote.Set("test", "user", "email@domain.tld", 1) // nolint:errcheck
+		bn := buildnumber.New(repo)
+		bn.Fetch(t.Context(), "origin", buildnumber.Filter{}, 0) // nolint:errcheck
+		bn.Set("test", "local", "email@domain.tld", 2)           // nolint:errcheck
+		bnRemote.Set("test", "remote", "email@domain.tld", 2)    // nolint:errcheck
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(stderr))
+
+		c := cmd.NewReconcileCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"--namespace", "test"})
+
+		err := c.Execute()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "3\n", stdout.String())
+		assert.Contains(t, stderr.String(), "build number 2 was handed out twice")
+		assert.Contains(t, stderr.String(), "renumbered local 2 -> 3")
+	})
+}
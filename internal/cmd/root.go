@@ -1,18 +1,139 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/anselstetter/git-build-number/internal/logger"
+	"github.com/anselstetter/git-build-number/internal/repository"
 	"github.com/spf13/cobra"
 )
 
-func NewRootCommand() *cobra.Command {
+const RemoteURLName = "origin"
+
+type Options struct {
+	Directory  string
+	GitDir     string
+	RemoteURL  string
+	Backend    string
+	SSHKey     string
+	KnownHosts []string
+	TLS        repository.TLS
+}
+
+type Opener func(options Options) (repository.Repository, func(), error)
+
+type LazyRepository struct {
+	repository.Repository
+	open  Opener
+	close func()
+}
+
+func NewLazyRepository(open Opener) *LazyRepository {
+	return &LazyRepository{open: open, close: func() {}}
+}
+
+func (r *LazyRepository) Open(options Options) error {
+	repo, closeRepo, err := r.open(options)
+	if err != nil {
+		return err
+	}
+	r.Repository = repo
+	r.close = closeRepo
+	return nil
+}
+
+func (r *LazyRepository) Close() {
+	r.close()
+}
+
+func NewRootCommand(repo *LazyRepository, logger logger.Logger) *cobra.Command {
+	var (
+		options Options
+		dryRun  bool
+		timeout time.Duration
+	)
 	cmd := &cobra.Command{
 		Use:   "git-build-number",
 		Short: "Manage build numbers within a Git repository",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return cmd.Help()
 		},
+		PersistentPreRunE: SilenceUsageOnErrorE(func(cmd *cobra.Command, args []string) error {
+			if options.RemoteURL != "" {
+				if !slices.Contains([]string{"inc", "next", "help", "version"}, cmd.Name()) {
+					return ErrRemoteURLCommand
+				}
+				if rev := cmd.Flags().Lookup("rev"); rev != nil && rev.Value.String() == "" {
+					return ErrRemoteURLRev
+				}
+			}
+			if options.TLS.ClientKey != "" && options.TLS.ClientCert == "" {
+				return ErrClientKey
+			}
+			if timeout > 0 {
+				ctx, cancel := context.WithTimeoutCause(cmd.Context(), timeout, fmt.Errorf("%w after %s", ErrTimeout, timeout))
+				context.AfterFunc(cmd.Context(), cancel)
+				cmd.SetContext(ctx)
+			}
+			if options.GitDir != "" && !filepath.IsAbs(options.GitDir) {
+				options.GitDir = filepath.Join(options.Directory, options.GitDir)
+			}
+			if err := repo.Open(options); err != nil {
+				return err
+			}
+			if options.TLS.Insecure {
+				logger.Stderrf("warning: TLS certificate verification is disabled, remotes can be impersonated\n")
+			}
+			if !dryRun {
+				return nil
+			}
+			return repo.DryRun(func(change repository.Change) {
+				LogChange(logger, change)
+			})
+		}),
 		SilenceErrors: true,
 	}
 	cmd.Root().CompletionOptions.DisableDefaultCmd = true
+	cmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "show which refs would change without writing anything")
+	cmd.PersistentFlags().StringVarP(&options.Directory, "directory", "C", ".", "run as if started in this path")
+	cmd.PersistentFlags().StringVar(&options.GitDir, "git-dir", "", "use this .git directory or bare repository")
+	cmd.PersistentFlags().StringVar(&options.Backend, "backend", "go-git", "the repository backend (go-git, git)")
+	cmd.PersistentFlags().StringVar(&options.SSHKey, "ssh-key", "", "authenticate to ssh remotes with this private key")
+	cmd.PersistentFlags().StringArrayVar(&options.KnownHosts, "known-hosts", []string{}, "verify ssh host keys against this known_hosts file")
+	cmd.PersistentFlags().StringVar(&options.TLS.CABundle, "ca-bundle", "", "trust the certificates in this PEM file for https remotes")
+	cmd.PersistentFlags().BoolVar(&options.TLS.Insecure, "insecure-skip-tls-verify", false, "don't verify the certificates of https remotes (insecure)")
+	cmd.PersistentFlags().StringVar(&options.TLS.ClientCert, "client-cert", "", "authenticate to https remotes with this PEM client certificate")
+	cmd.PersistentFlags().StringVar(&options.TLS.ClientKey, "client-key", "", "the private key of --client-cert, if it isn't part of the certificate file")
+	cmd.PersistentFlags().StringVar(&options.TLS.Proxy, "proxy", "", "connect to https remotes through this proxy (honours NO_PROXY)")
+	cmd.PersistentFlags().DurationVar(&timeout, "timeout", 0, "give up after this duration, e.g. 30s or 2m (0 waits forever)")
+	cmd.PersistentFlags().StringVar(&options.RemoteURL, "remote-url", "", "increment the build number of this remote without a local clone (requires --rev)")
 	return cmd
 }
+
+func LogChange(logger logger.Logger, change repository.Change) {
+	location := "local"
+	if change.Remote != "" {
+		location = change.Remote
+	}
+	oldHash, newHash := change.Old, change.New
+	if oldHash == "" {
+		oldHash = strings.Repeat("0", len(newHash))
+	}
+	if newHash == "" {
+		newHash = strings.Repeat("0", len(oldHash))
+	}
+	logger.Stderrf("would %s %s %s %s -> %s\n", change.Action(), location, change.Ref, oldHash, newHash)
+}
+
+func RemoteURL(cmd *cobra.Command) string {
+	flag := cmd.Flag("remote-url")
+	if flag == nil {
+		return ""
+	}
+	return flag.Value.String()
+}
@@ -0,0 +1,364 @@
+package cmd_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/anselstetter/git-build-number/internal/buildnumber"
+	"github.com/anselstetter/git-build-number/internal/cmd"
+	"github.com/anselstetter/git-build-number/internal/logger"
+	"github.com/anselstetter/git-build-number/internal/repository"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func addRemote(t *testing.T, remoteName string, initialCommit bool, local repository.Repository) repository.Repository {
+	t.Helper()
+
+	remote, remotePath, _ := repository.NewGitTempBareRepository(initialCommit)
+	t.Cleanup(func() {
+		_ = os.RemoveAll(*remotePath)
+	})
+	_ = local.AddRemote(remoteName, *remotePath)
+	return remote
+}
+
+func fetchShallow(t *testing.T, namespace string, builds int) repository.Repository {
+	t.Helper()
+
+	repo, _, _ := repository.NewGitInMemoryRepository(true)
+	remote := buildnumber.New(addRemote(t, "origin", true, repo))
+	for range builds {
+		remote.Inc(namespace, "user", "email@domain.tld", true) // nolint:errcheck
+	}
+	bn := buildnumber.New(repo)
+	bn.Fetch(t.Context(), "origin", buildnumber.Filter{}, 1) // nolint:errcheck
+	return repo
+}
+
+func opened(repo repository.Repository) *cmd.LazyRepository {
+	return cmd.NewLazyRepository(func(options cmd.Options) (repository.Repository, func(), error) {
+		return repo, func() {}, nil
+	})
+}
+
+func TestRoot(t *testing.T) {
+	silence := bytes.NewBuffer([]byte{})
+
+	t.Run("options", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		var options cmd.Options
+		lazy := cmd.NewLazyRepository(func(o cmd.Options) (repository.Repository, func(), error) {
+			options = o
+			return repo, func() {}, nil
+		})
+		logger := logger.New(logger.WithStdout(silence), logger.WithStderr(silence))
+
+		c := cmd.NewRootCommand(lazy, logger)
+		c.AddCommand(cmd.NewGetCommand(buildnumber.New(lazy), logger))
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"-C", "dir", "--git-dir=.git", "--backend", "git", "--known-hosts", "a", "--known-hosts", "b", "--proxy", "http://proxy", "get", "--create"})
+
+		err := c.Execute()
+
+		assert.NoError(t, err)
+		assert.Equal(t, cmd.Options{
+			Directory:  "dir",
+			GitDir:     filepath.Join("dir", ".git"),
+			Backend:    "git",
+			KnownHosts: []string{"a", "b"},
+			TLS:        repository.TLS{Proxy: "http://proxy"},
+		}, options)
+	})
+	t.Run("open fails", func(t *testing.T) {
+		t.Parallel()
+
+		lazy := cmd.NewLazyRepository(func(o cmd.Options) (repository.Repository, func(), error) {
+			return nil, nil, repository.ErrReferenceNotFound
+		})
+		logger := logger.New(logger.WithStdout(silence), logger.WithStderr(silence))
+
+		c := cmd.NewRootCommand(lazy, logger)
+		c.AddCommand(cmd.NewGetCommand(buildnumber.New(lazy), logger))
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"get"})
+
+		err := c.Execute()
+
+		assert.ErrorIs(t, err, repository.ErrReferenceNotFound)
+	})
+	t.Run("open fails without usage", func(t *testing.T) {
+		t.Parallel()
+
+		notExists := errors.New("repository does not exist")
+		lazy := cmd.NewLazyRepository(func(o cmd.Options) (repository.Repository, func(), error) {
+			return nil, nil, notExists
+		})
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(stderr))
+
+		c := cmd.NewRootCommand(lazy, logger)
+		c.AddCommand(cmd.NewGetCommand(buildnumber.New(lazy), logger))
+		c.SetOut(stdout)
+		c.SetErr(stderr)
+		c.SetArgs([]string{"get"})
+
+		err := c.Execute()
+
+		assert.ErrorIs(t, err, notExists)
+		assert.NotContains(t, stdout.String()+stderr.String(), "Usage:")
+	})
+	t.Run("timeout", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		logger := logger.New(logger.WithStdout(silence), logger.WithStderr(silence))
+
+		c := cmd.NewRootCommand(opened(repo), logger)
+		c.AddCommand(&cobra.Command{
+			Use: "wait",
+			RunE: func(cmd *cobra.Command, args []string) error {
+				<-cmd.Context().Done()
+				return context.Cause(cmd.Context())
+			},
+		})
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"--timeout", "1ms", "wait"})
+
+		err := c.ExecuteContext(t.Context())
+
+		assert.ErrorIs(t, err, cmd.ErrTimeout)
+		assert.EqualError(t, err, "timed out after 1ms")
+	})
+}
+
+func TestDryRun(t *testing.T) {
+	silence := bytes.NewBuffer([]byte{})
+	zero := strings.Repeat("0", 40)
+
+	t.Run("set", func(t *testing.T) {
+		t.Parallel()
+
+		repo, path, _ := repository.NewGitTempBareRepository(true)
+		t.Cleanup(func() {
+			_ = os.RemoveAll(*path)
+		})
+		bn := buildnumber.New(repo)
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(stderr))
+
+		c := cmd.NewRootCommand(opened(repo), logger)
+		c.AddCommand(cmd.NewSetCommand(bn, logger))
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"set", "5", "--dry-run"})
+
+		err := c.Execute()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "5\n", stdout.String())
+		assert.Regexp(t, fmt.Sprintf("^would create local refs/build-number/default %s -> [0-9a-f]{40}\n$", zero), stderr.String())
+
+		observer, _, _ := repository.NewGitInMemoryRepository(false)
+		_ = observer.AddRemote("origin", *path)
+		refs, err := observer.RemoteRefs(t.Context(), "origin", repository.WithPrefix("refs/build-number/"))
+		assert.NoError(t, err)
+		assert.Empty(t, refs)
+	})
+	t.Run("inc", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+		bn.Set("test", "user", "email@domain.tld", 1) // nolint:errcheck
+		before, _ := repo.Refs(repository.WithPrefix("refs/build-number/"))
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(stderr))
+
+		c := cmd.NewRootCommand(opened(repo), logger)
+		c.AddCommand(cmd.NewIncCommand(bn, logger))
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"inc", "--namespace", "test", "--force", "--dry-run"})
+
+		err := c.Execute()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "2\n", stdout.String())
+		assert.Regexp(t, fmt.Sprintf("^would update local refs/build-number/test %s -> [0-9a-f]{40}\n$", before[0].Hash), stderr.String())
+	})
+	t.Run("namespace delete", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+		bn.Set("test", "user", "email@domain.tld", 1) // nolint:errcheck
+		before, _ := repo.Refs(repository.WithPrefix("refs/build-number/"))
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(stderr))
+
+		c := cmd.NewRootCommand(opened(repo), logger)
+		c.AddCommand(cmd.NewNamespaceCommand(cmd.NewNamespaceDeleteCommand(bn, logger, strings.NewReader(""))))
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"namespace", "delete", "test", "--dry-run"})
+
+		err := c.Execute()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "", stdout.String())
+		assert.Equal(t, fmt.Sprintf("would delete local refs/build-number/test %s -> %s\n", before[0].Hash, zero), stderr.String())
+	})
+	t.Run("push", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		remote := addRemote(t, "origin", true, repo)
+		bn := buildnumber.New(repo)
+		bn.Set("test", "user", "email@domain.tld", 1) // nolint:errcheck
+		before, _ := repo.Refs(repository.WithPrefix("refs/build-number/"))
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(stderr))
+
+		c := cmd.NewRootCommand(opened(repo), logger)
+		c.AddCommand(cmd.NewPushCommand(bn, logger))
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"push", "--dry-run"})
+
+		err := c.Execute()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "", stdout.String())
+		assert.Equal(t, fmt.Sprintf("would create origin refs/build-number/test %s -> %s\n", zero, before[0].Hash), stderr.String())
+
+		remoteRefs, _ := remote.Refs(repository.WithPrefix("refs/build-number/"))
+		assert.Empty(t, remoteRefs)
+	})
+	t.Run("namespace mirror", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		remote := addRemote(t, "origin", true, repo)
+		bnRemote := buildnumber.New(remote)
+		bnRemote.Set("stale", "user", "email@domain.tld", 1) // nolint:errcheck
+		stale, _ := remote.Refs(repository.WithPrefix("refs/build-number/"))
+		bn := buildnumber.New(repo)
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(stderr))
+
+		c := cmd.NewRootCommand(opened(repo), logger)
+		c.AddCommand(cmd.NewNamespaceCommand(cmd.NewNamespaceMirrorCommand(bn, logger, strings.NewReader(""))))
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"namespace", "mirror", "--yes", "--dry-run"})
+
+		err := c.Execute()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "", stdout.String())
+		assert.Equal(t, fmt.Sprintf("would delete origin refs/build-number/stale %s -> %s\n", stale[0].Hash, zero), stderr.String())
+
+		remoteRefs, _ := remote.Refs(repository.WithPrefix("refs/build-number/"))
+		assert.Equal(t, stale, remoteRefs)
+	})
+}
+
+func TestRemoteURL(t *testing.T) {
+	silence := bytes.NewBuffer([]byte{})
+	rev := strings.Repeat("1", 40)
+
+	t.Run("inc", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(false)
+		repo.SetUnknownRevisions(true)
+		remote := addRemote(t, cmd.RemoteURLName, true, repo)
+		bn := buildnumber.New(repo)
+
+		stdout := bytes.NewBuffer([]byte{})
+
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(silence))
+
+		c := cmd.NewRootCommand(opened(repo), logger)
+		c.AddCommand(cmd.NewIncCommand(bn, logger))
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"--remote-url", "unused", "inc", "--rev", rev, "--force"})
+
+		err := c.Execute()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "2\n", stdout.String())
+
+		bnRemote := buildnumber.New(remote)
+		entry, err := bnRemote.Get("default", "user", "email@domain.tld", false)
+		assert.NoError(t, err)
+		assert.Equal(t, &buildnumber.Entry{Number: 2, Hash: rev}, entry)
+	})
+	t.Run("without --rev", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(false)
+		bn := buildnumber.New(repo)
+
+		output := bytes.NewBuffer([]byte{})
+		logger := logger.New(logger.WithStdout(output), logger.WithStderr(output))
+
+		c := cmd.NewRootCommand(opened(repo), logger)
+		c.AddCommand(cmd.NewIncCommand(bn, logger))
+		c.SetOut(output)
+		c.SetErr(output)
+		c.SetArgs([]string{"--remote-url", "unused", "inc"})
+
+		err := c.Execute()
+
+		assert.ErrorIs(t, err, cmd.ErrRemoteURLRev)
+		assert.NotContains(t, output.String(), "Usage:")
+	})
+	t.Run("unsupported command", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(false)
+		bn := buildnumber.New(repo)
+
+		logger := logger.New(logger.WithStdout(silence), logger.WithStderr(silence))
+
+		c := cmd.NewRootCommand(opened(repo), logger)
+		c.AddCommand(cmd.NewSetCommand(bn, logger))
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"--remote-url", "unused", "set", "5"})
+
+		err := c.Execute()
+
+		assert.ErrorIs(t, err, cmd.ErrRemoteURLCommand)
+	})
+}
@@ -14,6 +14,7 @@ func NewSetCommand(buildNumber buildnumber.BuildNumber, logger logger.Logger) *c
 		namespace string
 		user      string
 		email     string
+		rev       string
 	)
 	cmd := &cobra.Command{
 		Use:    "set <number>",
@@ -31,12 +32,13 @@ func NewSetCommand(buildNumber buildnumber.BuildNumber, logger logger.Logger) *c
 		},
 		RunE: SilenceUsageE(func(cmd *cobra.Command, args []string) error {
 			number, _ := strconv.ParseInt(args[0], 10, 64)
-			return Set(buildNumber, logger, namespace, user, email, number)
+			return Set(buildNumber.At(rev), logger, namespace, user, email, number)
 		}),
 	}
 	cmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "the namespace")
 	cmd.Flags().StringVarP(&user, "user", "u", "build number", "the author name")
 	cmd.Flags().StringVarP(&email, "email", "e", "not set", "the author email")
+	cmd.Flags().StringVar(&rev, "rev", "", "number this commit instead of HEAD")
 
 	return cmd
 }
@@ -2,6 +2,7 @@ package cmd_test
 
 import (
 	"bytes"
+	"strings"
 	"testing"
 
 	"github.com/anselstetter/git-build-number/internal/buildnumber"
@@ -101,4 +102,60 @@ func TestSet(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, repository.Author{Name: "First Last", Email: "email@test.tld"}, commits[0].Author)
 	})
+	t.Run("--rev", func(t *testing.T) {
+		t.Parallel()
+
+		repo, initial, _ := repository.NewGitInMemoryRepository(true)
+		_, _ = repo.Commit("refs/heads/main", "next", []byte("next"), "Next commit", repository.WithHead())
+		bn := buildnumber.New(repo)
+
+		logger := logger.New(logger.WithStdout(silence), logger.WithStderr(silence))
+
+		c := cmd.NewSetCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"--rev", "HEAD~1", "123"})
+
+		err := c.Execute()
+		assert.NoError(t, err)
+
+		entry, err := bn.Get("default", "", "", false)
+		assert.NoError(t, err)
+		assert.Equal(t, &buildnumber.Entry{Number: 123, Hash: initial.Hash}, entry)
+	})
+	t.Run("--rev unknown", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		logger := logger.New(logger.WithStdout(silence), logger.WithStderr(silence))
+
+		c := cmd.NewSetCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"--rev", "missing", "123"})
+
+		err := c.Execute()
+		assert.ErrorIs(t, err, repository.ErrRevisionNotFound)
+	})
+	t.Run("--rev unknown hash", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		logger := logger.New(logger.WithStdout(silence), logger.WithStderr(silence))
+
+		c := cmd.NewSetCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"--rev", strings.Repeat("1", 40), "123"})
+
+		err := c.Execute()
+		assert.ErrorIs(t, err, repository.ErrRevisionNotFound)
+
+		_, err = bn.Get("default", "", "", false)
+		assert.ErrorIs(t, err, buildnumber.ErrBuildNumberNotFound)
+	})
 }
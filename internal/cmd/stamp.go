@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"github.com/anselstetter/git-build-number/internal/buildnumber"
+	"github.com/anselstetter/git-build-number/internal/logger"
+	"github.com/anselstetter/git-build-number/internal/stamp"
+	"github.com/spf13/cobra"
+)
+
+const synopsisStamp = `Write the current build number into source files before a build.
+
+Formats:
+  go      print -ldflags -X arguments (key defaults to main.Version)
+  npm     the top-level "version" in package.json
+  gradle  versionCode in gradle.properties
+  plist   CFBundleVersion in Info.plist
+  dotnet  <Version> in Directory.Build.props
+  regex   replace the first capture group of every match of --pattern in the given files
+
+The key can be changed with --key, the files default to the usual file name of the format.`
+
+func NewStampCommand(buildNumber buildnumber.BuildNumber, logger logger.Logger) *cobra.Command {
+	var (
+		namespace string
+		key       string
+		pattern   string
+	)
+	cmd := &cobra.Command{
+		Use:   "stamp <format> [file]...",
+		Short: "Stamp the build number into source files",
+		Long:  synopsisStamp,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return ErrMissingFormat
+			}
+			format, err := stamp.ParseFormat(args[0])
+			if err != nil {
+				return err
+			}
+			if format == stamp.FormatRegex && len(args) < 2 {
+				return ErrMissingFile
+			}
+			return nil
+		},
+		RunE: SilenceUsageE(func(cmd *cobra.Command, args []string) error {
+			format, _ := stamp.ParseFormat(args[0])
+			return Stamp(buildNumber, logger, namespace, format, key, pattern, args[1:]...)
+		}),
+	}
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "the namespace")
+	cmd.Flags().StringVarP(&key, "key", "k", "", "the key to stamp (defaults depend on the format)")
+	cmd.Flags().StringVarP(&pattern, "pattern", "p", "", "the pattern for the regex format")
+	return cmd
+}
+
+func Stamp(buildNumber buildnumber.BuildNumber, logger logger.Logger, namespace string, format stamp.Format, key string, pattern string, files ...string) error {
+	entry, err := buildNumber.Get(namespace, "", "", false)
+	if err != nil {
+		return err
+	}
+	if key == "" {
+		key = format.DefaultKey()
+	}
+	if format == stamp.FormatGo {
+		logger.Stdoutln(stamp.LdFlags(key, entry.Number))
+		return nil
+	}
+	if format == stamp.FormatRegex {
+		key = pattern
+	}
+	replace, err := format.Replacer(key)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		files = []string{format.DefaultFile()}
+	}
+	if err := stamp.Files(files, replace, entry.Number); err != nil {
+		return err
+	}
+	logger.Stdoutln(entry.Number)
+	return nil
+}
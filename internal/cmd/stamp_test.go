@@ -0,0 +1,172 @@
+package cmd_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/anselstetter/git-build-number/internal/buildnumber"
+	"github.com/anselstetter/git-build-number/internal/cmd"
+	"github.com/anselstetter/git-build-number/internal/logger"
+	"github.com/anselstetter/git-build-number/internal/repository"
+	"github.com/anselstetter/git-build-number/internal/stamp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStamp(t *testing.T) {
+	silence := bytes.NewBuffer([]byte{})
+
+	t.Run("without args", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(stderr))
+
+		c := cmd.NewStampCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+
+		err := c.Execute()
+
+		assert.ErrorIs(t, err, cmd.ErrMissingFormat)
+		assert.Equal(t, "", stdout.String())
+		assert.Equal(t, "", stderr.String())
+	})
+	t.Run("invalid format", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		logger := logger.New(logger.WithStdout(silence), logger.WithStderr(silence))
+
+		c := cmd.NewStampCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"invalid"})
+
+		err := c.Execute()
+
+		assert.ErrorIs(t, err, stamp.ErrUnknownFormat)
+	})
+	t.Run("go", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+		bn.Set("test", "user", "email@domain.tld", 123) // nolint:errcheck
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(stderr))
+
+		c := cmd.NewStampCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"go", "--namespace", "test"})
+
+		err := c.Execute()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "-X main.Version=123\n", stdout.String())
+		assert.Equal(t, "", stderr.String())
+	})
+	t.Run("npm", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+		bn.Set("default", "user", "email@domain.tld", 7) // nolint:errcheck
+
+		path := filepath.Join(t.TempDir(), "package.json")
+		_ = os.WriteFile(path, []byte(`{"version": "0.0.0"}`), 0o644)
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(stderr))
+
+		c := cmd.NewStampCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"npm", path})
+
+		err := c.Execute()
+
+		assert.NoError(t, err)
+		content, _ := os.ReadFile(path)
+		assert.Equal(t, `{"version": "7"}`, string(content))
+		assert.Equal(t, "7\n", stdout.String())
+		assert.Equal(t, "", stderr.String())
+	})
+	t.Run("regex without file", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		logger := logger.New(logger.WithStdout(silence), logger.WithStderr(silence))
+
+		c := cmd.NewStampCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"regex", "--pattern", `(\d+)`})
+
+		err := c.Execute()
+
+		assert.ErrorIs(t, err, cmd.ErrMissingFile)
+	})
+	t.Run("regex", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+		bn.Set("default", "user", "email@domain.tld", 9) // nolint:errcheck
+
+		path := filepath.Join(t.TempDir(), "version.txt")
+		_ = os.WriteFile(path, []byte("build: 0\n"), 0o644)
+
+		logger := logger.New(logger.WithStdout(silence), logger.WithStderr(silence))
+
+		c := cmd.NewStampCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"regex", "--pattern", `build: (\d+)`, path})
+
+		err := c.Execute()
+
+		assert.NoError(t, err)
+		content, _ := os.ReadFile(path)
+		assert.Equal(t, "build: 9\n", string(content))
+	})
+	t.Run("regex with two matches", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+		bn.Set("default", "user", "email@domain.tld", 9) // nolint:errcheck
+
+		path := filepath.Join(t.TempDir(), "version.txt")
+		_ = os.WriteFile(path, []byte("build: 0\nname: app\nbuild: 1\n"), 0o644)
+
+		logger := logger.New(logger.WithStdout(silence), logger.WithStderr(silence))
+
+		c := cmd.NewStampCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"regex", "--pattern", `build: (\d+)`, path})
+
+		err := c.Execute()
+
+		assert.NoError(t, err)
+		content, _ := os.ReadFile(path)
+		assert.Equal(t, "build: 9\nname: app\nbuild: 9\n", string(content))
+	})
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/anselstetter/git-build-number/internal/buildnumber"
+	"github.com/anselstetter/git-build-number/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+const synopsisStatus = `Compare local namespaces with the namespaces of a remote.
+
+The remote namespaces are fetched into refs/remotes/<remote>/build-number/* first,
+local namespaces are left untouched.`
+
+func NewStatusCommand(buildNumber buildnumber.BuildNumber, logger logger.Logger) *cobra.Command {
+	var (
+		remote     string
+		namespaces []string
+		exclude    []string
+	)
+	cmd := &cobra.Command{
+		Use:    "status",
+		Short:  "Compare local and remote build numbers",
+		Long:   synopsisStatus,
+		PreRun: IgnoreAdditonalArgs(logger.StderrWriter(), 1),
+		RunE: SilenceUsageE(func(cmd *cobra.Command, args []string) error {
+			filter := buildnumber.Filter{Include: namespaces, Exclude: exclude}
+			return Status(cmd.Context(), buildNumber, logger, remote, filter)
+		}),
+	}
+	cmd.Flags().StringVarP(&remote, "remote", "r", "origin", "the remote")
+	cmd.Flags().StringArrayVarP(&namespaces, "namespace", "n", []string{}, "only these namespaces or globs (pr/*, release/**)")
+	cmd.Flags().StringArrayVar(&exclude, "exclude", []string{}, "skip these namespaces or globs")
+
+	return cmd
+}
+
+func Status(ctx context.Context, buildNumber buildnumber.BuildNumber, logger logger.Logger, remote string, filter buildnumber.Filter) error {
+	statuses, err := buildNumber.Status(ctx, remote, filter)
+	if err != nil {
+		return err
+	}
+	rows := [][]any{}
+	for _, status := range statuses {
+		rows = append(rows, []any{status.Name, status.State, number(status.Local), number(status.Remote)})
+	}
+	logger.StdoutRows(rows...)
+	return nil
+}
+
+func number(entry *buildnumber.Entry) any {
+	if entry == nil {
+		return "-"
+	}
+	return entry.Number
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"github.com/anselstetter/git-build-number/internal/buildnumber"
+	"github.com/anselstetter/git-build-number/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+const synopsisWorkspaceStatus = `Print the build number in the format of Bazel's --workspace_status_command.
+
+Use it with:
+  bazel build --workspace_status_command="git build-number workspace-status"`
+
+func NewWorkspaceStatusCommand(buildNumber buildnumber.BuildNumber, logger logger.Logger) *cobra.Command {
+	var (
+		namespace string
+	)
+	cmd := &cobra.Command{
+		Use:    "workspace-status",
+		Short:  "Print the build number for Bazel stamping",
+		Long:   synopsisWorkspaceStatus,
+		PreRun: IgnoreAdditonalArgs(logger.StderrWriter(), 1),
+		RunE: SilenceUsageE(func(cmd *cobra.Command, args []string) error {
+			return WorkspaceStatus(buildNumber, logger, namespace)
+		}),
+	}
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "the namespace")
+	return cmd
+}
+
+func WorkspaceStatus(buildNumber buildnumber.BuildNumber, logger logger.Logger, namespace string) error {
+	entry, err := buildNumber.Get(namespace, "", "", false)
+	if err != nil {
+		return err
+	}
+	logger.Stdoutf("STABLE_BUILD_NUMBER %d\n", entry.Number)
+	logger.Stdoutf("STABLE_BUILD_NUMBER_HASH %s\n", entry.Hash)
+	logger.Stdoutf("BUILD_NUMBER_NAMESPACE %s\n", namespace)
+	return nil
+}
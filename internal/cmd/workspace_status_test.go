@@ -0,0 +1,64 @@
+package cmd_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/anselstetter/git-build-number/internal/buildnumber"
+	"github.com/anselstetter/git-build-number/internal/cmd"
+	"github.com/anselstetter/git-build-number/internal/logger"
+	"github.com/anselstetter/git-build-number/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkspaceStatus(t *testing.T) {
+	silence := bytes.NewBuffer([]byte{})
+
+	t.Run("without build number", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(stderr))
+
+		c := cmd.NewWorkspaceStatusCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+
+		err := c.Execute()
+
+		assert.ErrorIs(t, err, buildnumber.ErrBuildNumberNotFound)
+		assert.Equal(t, "", stdout.String())
+		assert.Equal(t, "", stderr.String())
+	})
+	t.Run("--namespace", func(t *testing.T) {
+		t.Parallel()
+
+		repo, head, _ := repository.NewGitInMemoryRepository(true)
+		bn := buildnumber.New(repo)
+		bn.Set("test", "user", "email@domain.tld", 123) // nolint:errcheck
+
+		stdout := bytes.NewBuffer([]byte{})
+		stderr := bytes.NewBuffer([]byte{})
+
+		logger := logger.New(logger.WithStdout(stdout), logger.WithStderr(stderr))
+
+		c := cmd.NewWorkspaceStatusCommand(bn, logger)
+		c.SetOut(silence)
+		c.SetErr(silence)
+		c.SetArgs([]string{"--namespace", "test"})
+
+		err := c.Execute()
+
+		expected := fmt.Sprintf("STABLE_BUILD_NUMBER 123\nSTABLE_BUILD_NUMBER_HASH %s\nBUILD_NUMBER_NAMESPACE test\n", head.Hash)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, stdout.String())
+		assert.Equal(t, "", stderr.String())
+	})
+}
@@ -64,6 +64,35 @@ func (l Logger) StdoutTable(a ...any) {
 	}
 }
 
+func (l Logger) StdoutRows(rows ...[]any) {
+	cells := [][]string{}
+	widths := []int{}
+
+	for _, row := range rows {
+		line := []string{}
+		for i, value := range row {
+			cell := fmt.Sprintf("%+v", value)
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], len(cell))
+			line = append(line, cell)
+		}
+		cells = append(cells, line)
+	}
+	for _, line := range cells {
+		out := []string{}
+		for i, cell := range line {
+			if i == len(line)-1 {
+				out = append(out, cell)
+				continue
+			}
+			out = append(out, cell+strings.Repeat(" ", widths[i]-len(cell)))
+		}
+		l.Stdoutf("%s\n", strings.Join(out, " "))
+	}
+}
+
 func (l Logger) Stdoutln(a ...any) {
 	if _, err := fmt.Fprintln(l.options.stdout, a...); err != nil {
 		_ = "ignore"
@@ -50,6 +50,24 @@ Third                     {a:a b:123 c:123.123}
 	})
 }
 
+func TestRows(t *testing.T) {
+	t.Parallel()
+
+	stdout := bytes.NewBuffer([]byte{})
+	logger := logger.New(logger.WithStdout(stdout))
+
+	logger.StdoutRows(
+		[]any{"name", "state", "number"},
+		[]any{"default", "in sync", 123},
+		[]any{"a", "ahead"},
+	)
+	want := `name    state   number
+default in sync 123
+a       ahead
+`
+	assert.Equal(t, want, stdout.String())
+}
+
 func TestStdOutWriter(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/go-git/go-git/v6/plumbing/transport"
+	"github.com/go-git/go-git/v6/plumbing/transport/http"
+	"github.com/go-git/go-git/v6/plumbing/transport/ssh"
+	"github.com/go-git/go-git/v6/plumbing/transport/ssh/knownhosts"
+)
+
+var (
+	ErrInvalidSSHKey     = errors.New("ssh key is invalid")
+	ErrInvalidKnownHosts = errors.New("known_hosts is invalid")
+)
+
+var userinfo = regexp.MustCompile(`://[^/\s@]+@`)
+
+type Token struct {
+	Value    string
+	Username string
+	Host     string
+}
+
+type Auth struct {
+	Username         string
+	Password         string
+	Tokens           []Token
+	Netrc            string
+	SSHKey           string
+	SSHKeyPassphrase string
+	KnownHosts       []string
+}
+
+func AuthFromEnv(getenv func(key string) string) Auth {
+	auth := Auth{
+		Username:         getenv("GIT_BUILD_NUMBER_USERNAME"),
+		Password:         getenv("GIT_BUILD_NUMBER_PASSWORD"),
+		Netrc:            netrcPath(getenv),
+		SSHKey:           getenv("GIT_BUILD_NUMBER_SSH_KEY"),
+		SSHKeyPassphrase: getenv("GIT_BUILD_NUMBER_SSH_PASSPHRASE"),
+	}
+	if token := getenv("GIT_BUILD_NUMBER_TOKEN"); token != "" {
+		auth.Tokens = append(auth.Tokens, Token{Value: token, Username: "x-access-token"})
+	}
+	if token := getenv("GITHUB_TOKEN"); token != "" {
+		host := "github.com"
+		if server, err := url.Parse(getenv("GITHUB_SERVER_URL")); err == nil && server.Host != "" {
+			host = server.Host
+		}
+		auth.Tokens = append(auth.Tokens, Token{Value: token, Username: "x-access-token", Host: host})
+	}
+	if token := getenv("CI_JOB_TOKEN"); token != "" {
+		host := getenv("CI_SERVER_HOST")
+		if host == "" {
+			host = "gitlab.com"
+		}
+		auth.Tokens = append(auth.Tokens, Token{Value: token, Username: "gitlab-ci-token", Host: host})
+	}
+	return auth
+}
+
+func (a *Auth) method(remoteURL string) (transport.AuthMethod, error) {
+	endpoint, err := transport.NewEndpoint(remoteURL)
+	if err != nil {
+		return nil, nil
+	}
+	switch endpoint.Protocol {
+	case "http", "https":
+		return a.httpMethod(endpoint)
+	case "ssh":
+		return a.sshMethod(endpoint)
+	default:
+		return nil, nil
+	}
+}
+
+func (a *Auth) httpMethod(endpoint *transport.Endpoint) (transport.AuthMethod, error) {
+	if endpoint.Password != "" {
+		return nil, nil
+	}
+	username := func(fallback string) string {
+		switch {
+		case endpoint.User != "":
+			return endpoint.User
+		case a.Username != "":
+			return a.Username
+		default:
+			return fallback
+		}
+	}
+	if a.Password != "" {
+		return &http.BasicAuth{Username: username(""), Password: a.Password}, nil
+	}
+	for _, token := range a.Tokens {
+		if token.Host == "" || strings.EqualFold(token.Host, endpoint.Host) {
+			return &http.BasicAuth{Username: username(token.Username), Password: token.Value}, nil
+		}
+	}
+	entry, err := netrcLookup(a.Netrc, endpoint.Host)
+	if err != nil || entry == nil {
+		return nil, err
+	}
+	if endpoint.User != "" && entry.login != "" && entry.login != endpoint.User {
+		return nil, nil
+	}
+	return &http.BasicAuth{Username: username(entry.login), Password: entry.password}, nil
+}
+
+func (a *Auth) sshMethod(endpoint *transport.Endpoint) (transport.AuthMethod, error) {
+	if a.SSHKey == "" && len(a.KnownHosts) == 0 {
+		return nil, nil
+	}
+	user := endpoint.User
+	if user == "" {
+		user = "git"
+	}
+	helper := ssh.HostKeyCallbackHelper{}
+	if len(a.KnownHosts) > 0 {
+		db, err := knownhosts.NewDB(a.KnownHosts...)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidKnownHosts, err)
+		}
+		helper.HostKeyCallback = db.HostKeyCallback()
+	}
+	if a.SSHKey != "" {
+		keys, err := ssh.NewPublicKeysFromFile(user, a.SSHKey, a.SSHKeyPassphrase)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %s", ErrInvalidSSHKey, a.SSHKey, redact(err, a.SSHKeyPassphrase))
+		}
+		keys.HostKeyCallbackHelper = helper
+		return keys, nil
+	}
+	agent, err := ssh.NewSSHAgentAuth(user)
+	if err != nil {
+		return nil, err
+	}
+	agent.HostKeyCallbackHelper = helper
+	return agent, nil
+}
+
+func (a *Auth) sshCommand(remoteURL string) string {
+	endpoint, err := transport.NewEndpoint(remoteURL)
+	if err != nil || endpoint.Protocol != "ssh" || (a.SSHKey == "" && len(a.KnownHosts) == 0) {
+		return ""
+	}
+	command := []string{"ssh"}
+	if a.SSHKey != "" {
+		command = append(command, "-i", shellQuote(a.SSHKey), "-o", "IdentitiesOnly=yes")
+	}
+	if len(a.KnownHosts) > 0 {
+		command = append(command, "-o", shellQuote("UserKnownHostsFile="+strings.Join(a.KnownHosts, " ")))
+	}
+	return strings.Join(command, " ")
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func (a *Auth) secrets() []string {
+	if a == nil {
+		return nil
+	}
+	secrets := []string{a.Password, a.SSHKeyPassphrase}
+	for _, token := range a.Tokens {
+		secrets = append(secrets, token.Value)
+	}
+	return secrets
+}
+
+type redactedError struct {
+	err     error
+	message string
+}
+
+func (e *redactedError) Error() string {
+	return e.message
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+func redact(err error, secrets ...string) error {
+	if err == nil {
+		return nil
+	}
+	message := redactString(err.Error(), secrets...)
+	if message == err.Error() {
+		return err
+	}
+	return &redactedError{err: err, message: message}
+}
+
+func redactString(s string, secrets ...string) string {
+	s = userinfo.ReplaceAllString(s, "://***@")
+	for _, secret := range secrets {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, "***")
+		}
+	}
+	return s
+}
+
+func netrcPath(getenv func(key string) string) string {
+	if path := getenv("NETRC"); path != "" {
+		return path
+	}
+	if runtime.GOOS == "windows" {
+		if home := getenv("USERPROFILE"); home != "" {
+			return filepath.Join(home, "_netrc")
+		}
+		return ""
+	}
+	if home := getenv("HOME"); home != "" {
+		return filepath.Join(home, ".netrc")
+	}
+	return ""
+}
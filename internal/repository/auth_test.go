@@ -0,0 +1,230 @@
+package repository_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/anselstetter/git-build-number/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthFromEnv(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected repository.Auth
+	}{
+		{
+			name:     "empty",
+			env:      map[string]string{},
+			expected: repository.Auth{},
+		},
+		{
+			name: "basic auth and ssh",
+			env: map[string]string{
+				"GIT_BUILD_NUMBER_USERNAME":       "user",
+				"GIT_BUILD_NUMBER_PASSWORD":       "password",
+				"GIT_BUILD_NUMBER_SSH_KEY":        "/keys/id_ed25519",
+				"GIT_BUILD_NUMBER_SSH_PASSPHRASE": "passphrase",
+				"HOME":                            "/home/user",
+			},
+			expected: repository.Auth{
+				Username:         "user",
+				Password:         "password",
+				Netrc:            filepath.Join("/home/user", ".netrc"),
+				SSHKey:           "/keys/id_ed25519",
+				SSHKeyPassphrase: "passphrase",
+			},
+		},
+		{
+			name: "tokens",
+			env: map[string]string{
+				"GIT_BUILD_NUMBER_TOKEN": "a",
+				"GITHUB_TOKEN":           "b",
+				"GITHUB_SERVER_URL":      "https://github.example.com",
+				"CI_JOB_TOKEN":           "c",
+				"NETRC":                  "/etc/netrc",
+			},
+			expected: repository.Auth{
+				Netrc: "/etc/netrc",
+				Tokens: []repository.Token{
+					{Value: "a", Username: "x-access-token"},
+					{Value: "b", Username: "x-access-token", Host: "github.example.com"},
+					{Value: "c", Username: "gitlab-ci-token", Host: "gitlab.com"},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			auth := repository.AuthFromEnv(func(key string) string {
+				return test.env[key]
+			})
+			assert.Equal(t, test.expected, auth)
+		})
+	}
+}
+
+type authRepository interface {
+	repository.Repository
+	SetAuth(auth repository.Auth)
+}
+
+func newAuthRepository(t *testing.T, backend string) authRepository {
+	t.Helper()
+
+	if backend == "go-git" {
+		repo, _, _ := repository.NewGitInMemoryRepository(false)
+		return repo
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	repo, path, err := repository.NewCLITempBareRepository(false)
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		_ = os.RemoveAll(*path)
+	})
+	return repo
+}
+
+func TestAuth(t *testing.T) {
+	netrc := filepath.Join(t.TempDir(), ".netrc")
+	err := os.WriteFile(netrc, []byte(strings.Join([]string{
+		"machine example.com login other password other",
+		"macdef init",
+		"machine 127.0.0.1 login ignored password ignored",
+		"",
+		"machine 127.0.0.1",
+		"  login netrc",
+		"  password secret # comment",
+		"default login anonymous password default",
+	}, "\n")), 0o600)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		auth     repository.Auth
+		userinfo string
+		user     string
+		password string
+	}{
+		{name: "none", auth: repository.Auth{}},
+		{name: "basic auth", auth: repository.Auth{Username: "user", Password: "secret"}, user: "user", password: "secret"},
+		{name: "token", auth: repository.Auth{Tokens: []repository.Token{{Value: "secret", Username: "x-access-token"}}}, user: "x-access-token", password: "secret"},
+		{name: "token for this host", auth: repository.Auth{Tokens: []repository.Token{{Value: "secret", Username: "gitlab-ci-token", Host: "127.0.0.1"}}}, user: "gitlab-ci-token", password: "secret"},
+		{name: "token for another host", auth: repository.Auth{Tokens: []repository.Token{{Value: "secret", Username: "x-access-token", Host: "github.com"}}}},
+		{name: "token with username", auth: repository.Auth{Username: "user", Tokens: []repository.Token{{Value: "secret", Username: "x-access-token"}}}, user: "user", password: "secret"},
+		{name: "netrc", auth: repository.Auth{Netrc: netrc}, user: "netrc", password: "secret"},
+		{name: "missing netrc", auth: repository.Auth{Netrc: filepath.Join(t.TempDir(), ".netrc")}},
+		{name: "url", auth: repository.Auth{Password: "other"}, userinfo: "user:secret@", user: "user", password: "secret"},
+	}
+	for _, backend := range []string{"go-git", "git"} {
+		for _, test := range tests {
+			t.Run(backend+"/"+test.name, func(t *testing.T) {
+				t.Parallel()
+
+				var (
+					mu       sync.Mutex
+					user     string
+					password string
+				)
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					mu.Lock()
+					defer mu.Unlock()
+					var ok bool
+					user, password, ok = r.BasicAuth()
+					if !ok {
+						w.Header().Set("WWW-Authenticate", `Basic realm="test"`)
+						w.WriteHeader(http.StatusUnauthorized)
+						return
+					}
+					w.WriteHeader(http.StatusForbidden)
+				}))
+				t.Cleanup(server.Close)
+
+				repo := newAuthRepository(t, backend)
+				_ = repo.AddRemote("origin", strings.Replace(server.URL, "://", "://"+test.userinfo, 1)+"/repo.git")
+				repo.SetAuth(test.auth)
+
+				_, err := repo.RemoteRefs(t.Context(), "origin")
+				assert.Error(t, err)
+				assert.NotContains(t, err.Error(), "secret")
+
+				mu.Lock()
+				defer mu.Unlock()
+				assert.Equal(t, test.user, user)
+				assert.Equal(t, test.password, password)
+			})
+		}
+	}
+	t.Run("ssh key", func(t *testing.T) {
+		t.Parallel()
+
+		key := filepath.Join(t.TempDir(), "id_ed25519")
+		_ = os.WriteFile(key, []byte("not a key"), 0o600)
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		_ = repo.AddRemote("origin", "ssh://git@127.0.0.1:1/repo.git")
+		repo.SetAuth(repository.Auth{SSHKey: key, SSHKeyPassphrase: "secret"})
+
+		err := repo.Push(t.Context(), "refs/heads/main", "origin", false)
+		assert.ErrorIs(t, err, repository.ErrInvalidSSHKey)
+		assert.NotContains(t, err.Error(), "secret")
+	})
+	t.Run("known hosts", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		_ = repo.AddRemote("origin", "git@127.0.0.1:repo.git")
+		repo.SetAuth(repository.Auth{KnownHosts: []string{filepath.Join(t.TempDir(), "known_hosts")}})
+
+		err := repo.Fetch(t.Context(), "refs/heads/main", "origin", false)
+		assert.ErrorIs(t, err, repository.ErrInvalidKnownHosts)
+	})
+	t.Run("without remote", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(true)
+		repo.SetAuth(repository.Auth{Password: "secret"})
+
+		err := repo.Push(t.Context(), "refs/heads/main", "origin", false)
+		assert.ErrorIs(t, err, repository.ErrRemoteNotFound)
+	})
+}
+
+func TestSSHCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake ssh is a shell script")
+	}
+	bin := t.TempDir()
+	err := os.WriteFile(filepath.Join(bin, "ssh"), []byte("#!/bin/sh\nprintf '%s\\n' \"$@\" > \"$(dirname \"$0\")/args\"\nexit 1\n"), 0o755)
+	assert.NoError(t, err)
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	dir := t.TempDir()
+	key := filepath.Join(dir, "deploy key's")
+	knownHosts := []string{filepath.Join(dir, "known_hosts"), filepath.Join(dir, "known_hosts2")}
+	repo := newAuthRepository(t, "git")
+	_ = repo.AddRemote("origin", "ssh://git@127.0.0.1:1/repo.git")
+	repo.SetAuth(repository.Auth{SSHKey: key, KnownHosts: knownHosts})
+
+	_, err = repo.RemoteRefs(t.Context(), "origin")
+	assert.Error(t, err)
+
+	args, err := os.ReadFile(filepath.Join(bin, "args"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"-i", key,
+		"-o", "IdentitiesOnly=yes",
+		"-o", "UserKnownHostsFile=" + strings.Join(knownHosts, " "),
+	}, strings.Split(string(args), "\n")[:6])
+}
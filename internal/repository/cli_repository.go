@@ -0,0 +1,985 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-git/go-git/v6"
+	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/go-git/go-git/v6/plumbing/filemode"
+	"github.com/go-git/go-git/v6/plumbing/object"
+	"github.com/go-git/go-git/v6/plumbing/transport/http"
+)
+
+const credentialHelper = `!f() { if [ "$1" = get ]; then printf 'username=%s\npassword=%s\n' "$GIT_BUILD_NUMBER_CREDENTIAL_USERNAME" "$GIT_BUILD_NUMBER_CREDENTIAL_PASSWORD"; fi; }; f`
+
+type CLIRepository struct {
+	gitDir           string
+	report           ChangeFunc
+	refs             map[string]*cliRef
+	objects          string
+	objectEnv        []string
+	config           []cliConfig
+	noProxy          string
+	auth             *Auth
+	unknownRevisions bool
+}
+
+type cliConfig struct {
+	key   string
+	value string
+}
+
+type cliRef struct {
+	hash   string
+	target string
+}
+
+type commandError struct {
+	command string
+	stderr  string
+	code    int
+}
+
+func (e *commandError) Error() string {
+	if e.stderr == "" {
+		return fmt.Sprintf("git %s: exit status %d", e.command, e.code)
+	}
+	return fmt.Sprintf("git %s: %s", e.command, e.stderr)
+}
+
+func (c *CLIRepository) Head() (*Ref, error) {
+	name, err := c.target("HEAD")
+	if err != nil {
+		return nil, err
+	}
+	hash, err := c.resolveRef(name)
+	if err != nil {
+		return nil, err
+	}
+	return &Ref{
+		Path: name,
+		Name: path.Base(name),
+		Hash: hash,
+	}, nil
+}
+
+func (c *CLIRepository) Refs(opts ...RefsOption) ([]Ref, error) {
+	options := newRefsOptions(opts...)
+
+	out, err := c.git(nil, "for-each-ref", "--format=%(refname)%00%(symref)%00%(objectname)")
+	if err != nil {
+		return nil, err
+	}
+	all := map[string]Ref{}
+	for line := range strings.Lines(string(out)) {
+		fields := strings.Split(strings.TrimSuffix(line, "\n"), "\x00")
+		if len(fields) != 3 {
+			continue
+		}
+		all[fields[0]] = Ref{Path: fields[0], Name: path.Base(fields[0]), Hash: fields[2], Target: fields[1]}
+	}
+	for name, ref := range c.refs {
+		if ref == nil || name == "HEAD" {
+			delete(all, name)
+			continue
+		}
+		r := Ref{Path: name, Name: path.Base(name), Hash: ref.hash, Target: ref.target}
+		if ref.target != "" {
+			r.Hash, _ = c.resolveRef(name)
+		}
+		all[name] = r
+	}
+	refs := []Ref{}
+	for _, ref := range all {
+		if options.prefix != nil && !strings.HasPrefix(ref.Path, *options.prefix) {
+			continue
+		}
+		if ref.Target != "" && !options.symbolic {
+			continue
+		}
+		refs = append(refs, ref)
+	}
+	slices.SortFunc(refs, func(a, b Ref) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return refs, nil
+}
+
+func (c *CLIRepository) RemoteRefs(ctx context.Context, remoteName string, opts ...RefsOption) ([]Ref, error) {
+	options := newRefsOptions(opts...)
+
+	if err := c.remote(remoteName); err != nil {
+		return nil, err
+	}
+	out, err := c.gitRemote(ctx, remoteName, "ls-remote", "--refs", remoteName)
+	if err != nil {
+		return nil, err
+	}
+	refs := []Ref{}
+	for line := range strings.Lines(string(out)) {
+		hash, name, ok := strings.Cut(strings.TrimSuffix(line, "\n"), "\t")
+		if !ok {
+			continue
+		}
+		if options.prefix != nil && !strings.HasPrefix(name, *options.prefix) {
+			continue
+		}
+		refs = append(refs, Ref{Path: name, Name: path.Base(name), Hash: hash})
+	}
+	return refs, nil
+}
+
+func (c *CLIRepository) Content(refName string, fileName string) (*[]byte, error) {
+	hash, err := c.resolveRef(refName)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := c.treeEntries(hash)
+	if err != nil {
+		return nil, err
+	}
+	index := slices.IndexFunc(entries, func(entry object.TreeEntry) bool { return entry.Name == fileName })
+	if index < 0 {
+		return nil, ErrFileNotFound
+	}
+	content, err := c.git(nil, "cat-file", "blob", entries[index].Hash.String())
+	if err != nil {
+		return nil, ErrObjectNotFound
+	}
+	return &content, nil
+}
+
+func (c *CLIRepository) Commits(refName string, opts ...CommitsOption) ([]Commit, error) {
+	options := newCommitsOptions(opts...)
+
+	hash, err := c.resolveRef(refName)
+	if err != nil {
+		return nil, err
+	}
+	shallows, err := c.shallows()
+	if err != nil {
+		return nil, err
+	}
+	out, err := c.git(nil, "rev-list", hash)
+	if err != nil {
+		return nil, err
+	}
+	objects, err := c.git(out, "cat-file", "--batch")
+	if err != nil {
+		return nil, err
+	}
+	commits := []Commit{}
+	for len(objects) > 0 {
+		decoded, rest, err := decodeBatchCommit(objects)
+		if err != nil {
+			return nil, err
+		}
+		objects = rest
+
+		commit := toCommit(decoded)
+		commits = append(commits, commit)
+
+		if options.matches(commit) {
+			return []Commit{commit}, nil
+		}
+		if decoded.NumParents() > 0 && slices.Contains(shallows, commit.Hash) {
+			return nil, fmt.Errorf("%w: %s", ErrShallowHistory, refName)
+		}
+	}
+	if options.filtered() {
+		return []Commit{}, nil
+	}
+	return commits, nil
+}
+
+func (c *CLIRepository) Lookup(hash string) (*Commit, error) {
+	content, err := c.git(nil, "cat-file", "commit", hash)
+	if err != nil {
+		return nil, ErrObjectNotFound
+	}
+	decoded, err := decodeCommit(hash, content)
+	if err != nil {
+		return nil, err
+	}
+	commit := toCommit(decoded)
+	return &commit, nil
+}
+
+func (c *CLIRepository) Resolve(rev string) (string, error) {
+	out, err := c.git(nil, "rev-parse", "-q", "--verify", "--end-of-options", rev+"^{commit}")
+	if err == nil {
+		return strings.TrimSpace(string(out)), nil
+	}
+	format, formatErr := c.ObjectFormat()
+	if c.unknownRevisions && formatErr == nil && format.Valid(rev) {
+		return strings.ToLower(rev), nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrRevisionNotFound, rev)
+}
+
+func (c *CLIRepository) Commit(refName, fileName string, content []byte, msg string, opts ...CommitOption) (*Ref, error) {
+	options := newCommitOptions(opts...)
+	format, err := c.ObjectFormat()
+	if err != nil {
+		return nil, err
+	}
+	name, err := c.target(refName)
+	if err != nil {
+		return nil, err
+	}
+	parents := []plumbing.Hash{}
+	old := ""
+	entries := []object.TreeEntry{}
+
+	parent, err := c.resolveRef(name)
+	if err == nil {
+		parents = append(parents, plumbing.NewHash(parent))
+		old = parent
+
+		parentEntries, err := c.treeEntries(parent)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range parentEntries {
+			if entry.Name != fileName {
+				entries = append(entries, entry)
+			}
+		}
+	} else if !errors.Is(err, ErrReferenceNotFound) {
+		return nil, err
+	}
+
+	blobHash, err := c.storeObject(plumbing.BlobObject, content)
+	if err != nil {
+		return nil, err
+	}
+	entries = append(entries, object.TreeEntry{
+		Name: fileName,
+		Mode: filemode.Regular,
+		Hash: plumbing.NewHash(blobHash),
+	})
+	slices.SortFunc(entries, func(a, b object.TreeEntry) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	treeHash, err := c.encodeObject(format, &object.Tree{Entries: entries})
+	if err != nil {
+		return nil, err
+	}
+	when, err := commitTime(options.when)
+	if err != nil {
+		return nil, err
+	}
+	extraHeaders := []object.ExtraHeader{}
+	for _, header := range options.headers {
+		extraHeaders = append(extraHeaders, object.ExtraHeader{Key: header.Key, Value: header.Value})
+	}
+	commitHash, err := c.encodeObject(format, &object.Commit{
+		Author: object.Signature{
+			Name:  options.author.Name,
+			Email: options.author.Email,
+			When:  when,
+		},
+		Message:      msg,
+		TreeHash:     plumbing.NewHash(treeHash),
+		ParentHashes: parents,
+		ExtraHeaders: extraHeaders,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := c.updateRef(name, commitHash, old, true); err != nil {
+		return nil, err
+	}
+	c.changed(Change{Ref: name, Old: old, New: commitHash})
+
+	if options.setHead {
+		if err := c.symbolicRef("HEAD", name); err != nil {
+			return nil, err
+		}
+	}
+	return &Ref{
+		Path: name,
+		Name: path.Base(name),
+		Hash: commitHash,
+	}, nil
+}
+
+func (c *CLIRepository) Update(refName string, hash string, oldHash string) error {
+	name, err := c.target(refName)
+	if err != nil {
+		return err
+	}
+	old, err := c.resolveRef(name)
+	if err != nil && !errors.Is(err, ErrReferenceNotFound) {
+		return err
+	}
+	if _, err := c.git(nil, "cat-file", "-e", hash+"^{commit}"); err != nil {
+		return ErrObjectNotFound
+	}
+	if err := c.updateRef(name, hash, oldHash, oldHash != ""); err != nil {
+		return err
+	}
+	c.changed(Change{Ref: name, Old: old, New: hash})
+
+	return nil
+}
+
+func (c *CLIRepository) Alias(refName string, target string) error {
+	old := ""
+
+	current, err := c.readRef(refName)
+	if err != nil {
+		return err
+	}
+	if current != nil {
+		old = current.target
+	}
+	if err := c.symbolicRef(refName, target); err != nil {
+		return err
+	}
+	c.changed(Change{Ref: refName, Old: old, New: target})
+
+	return nil
+}
+
+func (c *CLIRepository) Delete(refName string) error {
+	current, err := c.readRef(refName)
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		return ErrReferenceNotFound
+	}
+	old := current.hash
+	if current.target != "" {
+		format, err := c.ObjectFormat()
+		if err != nil {
+			return err
+		}
+		old = strings.Repeat("0", format.HexSize())
+	}
+	if c.refs != nil {
+		c.refs[refName] = nil
+	} else if _, err := c.git(nil, "update-ref", "-d", "--no-deref", refName); err != nil {
+		return err
+	}
+	c.changed(Change{Ref: refName, Old: old})
+
+	return nil
+}
+
+func (c *CLIRepository) DeleteRemote(ctx context.Context, refName string, remoteName string) error {
+	refs, err := c.RemoteRefs(ctx, remoteName, WithPrefix(refName))
+	if err != nil {
+		return err
+	}
+	index := slices.IndexFunc(refs, func(ref Ref) bool { return ref.Path == refName })
+	if index < 0 {
+		return ErrReferenceNotFound
+	}
+	if c.report != nil {
+		c.changed(Change{Remote: remoteName, Ref: refName, Old: refs[index].Hash})
+		return nil
+	}
+	return c.push(ctx, remoteName, ":"+refName)
+}
+
+func (c *CLIRepository) Fetch(ctx context.Context, refName string, remoteName string, force bool, opts ...FetchOption) error {
+	options := newFetchOptions(opts...)
+	destination := refName
+	if options.destination != nil {
+		destination = *options.destination
+	}
+	if err := c.remote(remoteName); err != nil {
+		return err
+	}
+	shallows, err := c.shallows()
+	if err != nil {
+		return err
+	}
+	depth := options.depth
+	if depth == 0 && len(shallows) > 0 {
+		depth = math.MaxInt32
+	}
+	args := []string{"fetch", "--no-tags"}
+	if depth > 0 {
+		args = append(args, fmt.Sprintf("--depth=%d", depth))
+	}
+	if c.refs != nil {
+		return c.fetchDryRun(ctx, refName, remoteName, destination, args)
+	}
+	spec := fmt.Sprintf("%s:%s", refName, destination)
+	if force {
+		spec = "+" + spec
+	}
+	_, err = c.gitRemote(ctx, remoteName, append(args, remoteName, spec)...)
+	return mapCommandError(err)
+}
+
+func (c *CLIRepository) fetchDryRun(ctx context.Context, refName string, remoteName string, destination string, args []string) error {
+	prefix, wildcard := strings.CutSuffix(refName, "*")
+	refs, err := c.RemoteRefs(ctx, remoteName, WithPrefix(prefix))
+	if err != nil {
+		return err
+	}
+	matched := []string{}
+	for _, ref := range refs {
+		if wildcard || ref.Path == refName {
+			matched = append(matched, ref.Path)
+		}
+	}
+	if len(matched) == 0 {
+		if wildcard {
+			return nil
+		}
+		return ErrReferenceNotFound
+	}
+	args = append(args, "--no-write-fetch-head", remoteName)
+	if _, err := c.gitRemote(ctx, remoteName, append(args, matched...)...); err != nil {
+		return mapCommandError(err)
+	}
+	for _, ref := range refs {
+		if !slices.Contains(matched, ref.Path) {
+			continue
+		}
+		name := destination
+		if wildcard {
+			name = strings.TrimSuffix(destination, "*") + strings.TrimPrefix(ref.Path, prefix)
+		}
+		c.refs[name] = &cliRef{hash: ref.Hash}
+	}
+	return nil
+}
+
+func (c *CLIRepository) Push(ctx context.Context, refName string, remoteName string, force bool) error {
+	if err := c.remote(remoteName); err != nil {
+		return err
+	}
+	if c.report != nil {
+		return reportPush(ctx, c, c.report, refName, remoteName, false)
+	}
+	spec := fmt.Sprintf("%s:%s", refName, refName)
+	if force {
+		spec = "+" + spec
+	}
+	return c.push(ctx, remoteName, spec)
+}
+
+func (c *CLIRepository) Mirror(ctx context.Context, refName string, remoteName string) error {
+	if err := c.remote(remoteName); err != nil {
+		return err
+	}
+	if c.report != nil {
+		return reportPush(ctx, c, c.report, refName+"*", remoteName, true)
+	}
+	localRefs, err := c.Refs(WithPrefix(refName))
+	if err != nil {
+		return err
+	}
+	remoteRefs, err := c.RemoteRefs(ctx, remoteName, WithPrefix(refName))
+	if err != nil {
+		return err
+	}
+	specs := []string{fmt.Sprintf("+%s*:%s*", refName, refName)}
+	for _, ref := range remoteRefs {
+		if !slices.ContainsFunc(localRefs, func(local Ref) bool { return local.Path == ref.Path }) {
+			specs = append(specs, ":"+ref.Path)
+		}
+	}
+	return c.push(ctx, remoteName, specs...)
+}
+
+func (c *CLIRepository) AddRemote(name string, urls ...string) error {
+	if _, err := c.git(nil, append([]string{"remote", "add", name}, urls[:min(1, len(urls))]...)...); err != nil {
+		return err
+	}
+	for _, url := range urls[min(1, len(urls)):] {
+		if _, err := c.git(nil, "remote", "set-url", "--add", name, url); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *CLIRepository) Config(section string) ([]ConfigOption, error) {
+	out, err := c.git(nil, "config", "--null", "--get-regexp", "^"+regexp.QuoteMeta(section)+`\.`)
+	var commandErr *commandError
+	if errors.As(err, &commandErr) && commandErr.code == 1 {
+		return []ConfigOption{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	options := []ConfigOption{}
+	subsections := []string{}
+	grouped := map[string][]ConfigOption{}
+	for _, entry := range strings.Split(strings.TrimSuffix(string(out), "\x00"), "\x00") {
+		name, value, _ := strings.Cut(entry, "\n")
+		name = name[len(section)+1:]
+		subsection, key := "", name
+		if index := strings.LastIndex(name, "."); index >= 0 {
+			subsection, key = name[:index], name[index+1:]
+		}
+		if subsection == "" {
+			options = append(options, ConfigOption{Key: key, Value: value})
+			continue
+		}
+		if !slices.Contains(subsections, subsection) {
+			subsections = append(subsections, subsection)
+		}
+		grouped[subsection] = append(grouped[subsection], ConfigOption{Subsection: subsection, Key: key, Value: value})
+	}
+	for _, subsection := range subsections {
+		options = append(options, grouped[subsection]...)
+	}
+	return options, nil
+}
+
+func (c *CLIRepository) ObjectFormat() (ObjectFormat, error) {
+	out, err := c.git(nil, "rev-parse", "--show-object-format")
+	if err != nil {
+		return "", err
+	}
+	return ObjectFormat(strings.TrimSpace(string(out))), nil
+}
+
+func (c *CLIRepository) DryRun(report ChangeFunc) error {
+	out, err := c.git(nil, "rev-parse", "--git-path", "objects")
+	if err != nil {
+		return err
+	}
+	objects := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(objects) {
+		objects = filepath.Join(c.gitDir, objects)
+	}
+	// New objects go to a temporary object directory, like git does for
+	// incoming pushes, so the repository can still read them but never keeps them.
+	dir, err := os.MkdirTemp("", "git-build-number-dry-run-")
+	if err != nil {
+		return err
+	}
+	if err := c.Close(); err != nil {
+		return err
+	}
+	c.objects = dir
+	c.objectEnv = []string{"GIT_OBJECT_DIRECTORY=" + dir, "GIT_ALTERNATE_OBJECT_DIRECTORIES=" + objects}
+	c.report = report
+	c.refs = map[string]*cliRef{}
+
+	return nil
+}
+
+func (c *CLIRepository) Close() error {
+	if c.objects == "" {
+		return nil
+	}
+	err := os.RemoveAll(c.objects)
+	c.objects = ""
+	c.objectEnv = nil
+	return err
+}
+
+func (c *CLIRepository) git(stdin []byte, args ...string) ([]byte, error) {
+	return c.gitContext(context.Background(), stdin, args...)
+}
+
+func (c *CLIRepository) gitContext(ctx context.Context, stdin []byte, args ...string) ([]byte, error) {
+	return runGit(ctx, "", stdin, append(configEnv(c.config), c.objectEnv...), []string{"--git-dir", c.gitDir}, args...)
+}
+
+func (c *CLIRepository) gitRemote(ctx context.Context, remoteName string, args ...string) ([]byte, error) {
+	env, err := c.credentialEnv(remoteName)
+	if err != nil {
+		return nil, err
+	}
+	if c.noProxy != "" {
+		env = append(env, "NO_PROXY="+c.noProxy, "no_proxy="+c.noProxy)
+	}
+	return runGit(ctx, "", nil, append(env, c.objectEnv...), []string{"--git-dir", c.gitDir}, args...)
+}
+
+func (c *CLIRepository) credentialEnv(remoteName string) ([]string, error) {
+	if c.auth == nil {
+		return configEnv(c.config), nil
+	}
+	out, err := c.git(nil, "remote", "get-url", remoteName)
+	if err != nil {
+		return nil, ErrRemoteNotFound
+	}
+	remoteURL := strings.TrimSpace(string(out))
+	// git runs ssh itself, so the key and the known_hosts files are passed
+	// on the command line of ssh instead of being loaded here.
+	if command := c.auth.sshCommand(remoteURL); command != "" {
+		return append(configEnv(c.config), "GIT_SSH_COMMAND="+command), nil
+	}
+	method, err := c.auth.method(remoteURL)
+	if err != nil {
+		return nil, err
+	}
+	basic, ok := method.(*http.BasicAuth)
+	if !ok {
+		return configEnv(c.config), nil
+	}
+	// The credentials are handed to git through the environment of a
+	// helper, so they never show up in the arguments or in error messages.
+	config := append(slices.Clone(c.config),
+		cliConfig{key: "credential.helper", value: ""},
+		cliConfig{key: "credential.helper", value: credentialHelper},
+	)
+	return append(configEnv(config),
+		"GIT_BUILD_NUMBER_CREDENTIAL_USERNAME="+basic.Username,
+		"GIT_BUILD_NUMBER_CREDENTIAL_PASSWORD="+basic.Password,
+	), nil
+}
+
+func (c *CLIRepository) SetAuth(auth Auth) {
+	c.auth = &auth
+}
+
+func (c *CLIRepository) SetUnknownRevisions(allow bool) {
+	c.unknownRevisions = allow
+}
+
+func (c *CLIRepository) SetTLS(config TLS) error {
+	if config.ClientKey != "" && config.ClientCert == "" {
+		return fmt.Errorf("%w: %s", ErrMissingClientCert, config.ClientKey)
+	}
+	options := map[string]string{
+		"http.sslCAInfo": config.CABundle,
+		"http.sslCert":   config.ClientCert,
+		"http.sslKey":    config.ClientKey,
+		"http.proxy":     config.Proxy,
+	}
+	if config.Insecure {
+		options["http.sslVerify"] = "false"
+	}
+	c.config = []cliConfig{}
+	for _, key := range slices.Sorted(maps.Keys(options)) {
+		if options[key] == "" {
+			continue
+		}
+		c.config = append(c.config, cliConfig{key: key, value: options[key]})
+	}
+	c.noProxy = config.NoProxy
+	return nil
+}
+
+func configEnv(config []cliConfig) []string {
+	if len(config) == 0 {
+		return nil
+	}
+	env := make([]string, 0, 2*len(config)+1)
+	for i, option := range config {
+		env = append(env, fmt.Sprintf("GIT_CONFIG_KEY_%d=%s", i, option.key), fmt.Sprintf("GIT_CONFIG_VALUE_%d=%s", i, option.value))
+	}
+	return append(env, fmt.Sprintf("GIT_CONFIG_COUNT=%d", len(config)))
+}
+
+func (c *CLIRepository) readRef(name string) (*cliRef, error) {
+	if ref, ok := c.refs[name]; ok {
+		return ref, nil
+	}
+	if name == "HEAD" {
+		if out, err := c.git(nil, "symbolic-ref", "-q", name); err == nil {
+			return &cliRef{target: strings.TrimSpace(string(out))}, nil
+		}
+		if out, err := c.git(nil, "rev-parse", "-q", "--verify", name); err == nil {
+			return &cliRef{hash: strings.TrimSpace(string(out))}, nil
+		}
+		return nil, nil
+	}
+	out, err := c.git(nil, "for-each-ref", "--format=%(refname)%00%(symref)%00%(objectname)", name)
+	if err != nil {
+		return nil, err
+	}
+	for line := range strings.Lines(string(out)) {
+		fields := strings.Split(strings.TrimSuffix(line, "\n"), "\x00")
+		if len(fields) == 3 && fields[0] == name {
+			if fields[1] != "" {
+				return &cliRef{target: fields[1]}, nil
+			}
+			return &cliRef{hash: fields[2]}, nil
+		}
+	}
+	if out, err := c.git(nil, "symbolic-ref", "-q", name); err == nil {
+		return &cliRef{target: strings.TrimSpace(string(out))}, nil
+	}
+	return nil, nil
+}
+
+func (c *CLIRepository) target(name string) (string, error) {
+	for range 10 {
+		ref, err := c.readRef(name)
+		if err != nil {
+			return "", err
+		}
+		if ref == nil || ref.target == "" {
+			return name, nil
+		}
+		name = ref.target
+	}
+	return "", ErrReferenceNotFound
+}
+
+func (c *CLIRepository) resolveRef(name string) (string, error) {
+	name, err := c.target(name)
+	if err != nil {
+		return "", err
+	}
+	ref, err := c.readRef(name)
+	if err != nil {
+		return "", err
+	}
+	if ref == nil {
+		return "", ErrReferenceNotFound
+	}
+	return ref.hash, nil
+}
+
+func (c *CLIRepository) updateRef(name string, hash string, old string, verify bool) error {
+	if c.refs == nil {
+		args := []string{"update-ref", name, hash}
+		if verify {
+			args = append(args, old)
+		}
+		_, err := c.git(nil, args...)
+		return mapCommandError(err)
+	}
+	if verify {
+		current, err := c.resolveRef(name)
+		if err != nil && !errors.Is(err, ErrReferenceNotFound) {
+			return err
+		}
+		if current != old {
+			return ErrReferenceChanged
+		}
+	}
+	c.refs[name] = &cliRef{hash: hash}
+	return nil
+}
+
+func (c *CLIRepository) symbolicRef(name string, target string) error {
+	if c.refs != nil {
+		c.refs[name] = &cliRef{target: target}
+		return nil
+	}
+	_, err := c.git(nil, "symbolic-ref", name, target)
+	return err
+}
+
+func (c *CLIRepository) remote(name string) error {
+	if _, err := c.git(nil, "remote", "get-url", name); err != nil {
+		return ErrRemoteNotFound
+	}
+	return nil
+}
+
+func (c *CLIRepository) push(ctx context.Context, remoteName string, specs ...string) error {
+	_, err := c.gitRemote(ctx, remoteName, append([]string{"push", "--atomic", "--porcelain", remoteName}, specs...)...)
+	return mapCommandError(err)
+}
+
+func (c *CLIRepository) shallows() ([]string, error) {
+	out, err := c.git(nil, "rev-parse", "--git-path", "shallow")
+	if err != nil {
+		return nil, err
+	}
+	file := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(c.gitDir, file)
+	}
+	content, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return []string{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(content)), nil
+}
+
+func (c *CLIRepository) treeEntries(commit string) ([]object.TreeEntry, error) {
+	out, err := c.git(nil, "ls-tree", "-z", "--end-of-options", commit)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTree, commit)
+	}
+	entries := []object.TreeEntry{}
+	for _, line := range strings.Split(strings.TrimSuffix(string(out), "\x00"), "\x00") {
+		if line == "" {
+			continue
+		}
+		info, name, ok := strings.Cut(line, "\t")
+		fields := strings.Fields(info)
+		if !ok || len(fields) != 3 {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidTree, commit)
+		}
+		mode, err := filemode.New(fields[0])
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, object.TreeEntry{Name: name, Mode: mode, Hash: plumbing.NewHash(fields[2])})
+	}
+	return entries, nil
+}
+
+func (c *CLIRepository) storeObject(objectType plumbing.ObjectType, content []byte) (string, error) {
+	out, err := c.git(content, "hash-object", "-t", objectType.String(), "-w", "--stdin")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func (c *CLIRepository) encodeObject(format ObjectFormat, obj object.Object) (string, error) {
+	mem := plumbing.NewMemoryObject(plumbing.FromObjectFormat(format.config()))
+	if err := obj.Encode(mem); err != nil {
+		return "", err
+	}
+	reader, err := mem.Reader()
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+	return c.storeObject(mem.Type(), content)
+}
+
+func (c *CLIRepository) changed(change Change) {
+	if c.report != nil {
+		c.report(change)
+	}
+}
+
+func decodeBatchCommit(batch []byte) (*object.Commit, []byte, error) {
+	header, rest, ok := bytes.Cut(batch, []byte{'\n'})
+	fields := strings.Fields(string(header))
+	if !ok || len(fields) != 3 || fields[1] != "commit" {
+		return nil, nil, fmt.Errorf("%w: %s", ErrObjectNotFound, fields[0])
+	}
+	size, err := strconv.Atoi(fields[2])
+	if err != nil || len(rest) < size+1 {
+		return nil, nil, fmt.Errorf("%w: %s", ErrObjectNotFound, fields[0])
+	}
+	commit, err := decodeCommit(fields[0], rest[:size])
+	if err != nil {
+		return nil, nil, err
+	}
+	return commit, rest[size+1:], nil
+}
+
+func decodeCommit(hash string, content []byte) (*object.Commit, error) {
+	mem := &plumbing.MemoryObject{}
+	mem.SetType(plumbing.CommitObject)
+	if _, err := mem.Write(content); err != nil {
+		return nil, err
+	}
+	commit := &object.Commit{}
+	if err := commit.Decode(mem); err != nil {
+		return nil, err
+	}
+	commit.Hash = plumbing.NewHash(hash)
+	return commit, nil
+}
+
+func mapCommandError(err error) error {
+	var commandErr *commandError
+	if !errors.As(err, &commandErr) {
+		return err
+	}
+	switch {
+	case strings.Contains(commandErr.stderr, "couldn't find remote ref"):
+		return ErrReferenceNotFound
+	case strings.Contains(commandErr.stderr, "does not match any"):
+		return ErrReferenceNotFound
+	case strings.Contains(commandErr.stderr, "cannot lock ref"):
+		return ErrReferenceChanged
+	case strings.Contains(commandErr.stderr, "[rejected]"), strings.Contains(commandErr.stderr, "stale info"):
+		return ErrReferenceChanged
+	case strings.Contains(commandErr.stderr, "atomic push failed"):
+		return ErrReferenceChanged
+	default:
+		return err
+	}
+}
+
+func runGit(ctx context.Context, dir string, stdin []byte, env []string, global []string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", append(global, args...)...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "LC_ALL=C")
+	cmd.Env = append(cmd.Env, env...)
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	stdout := bytes.Buffer{}
+	stderr := bytes.Buffer{}
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if ctxErr := ctx.Err(); err != nil && ctxErr != nil {
+		return nil, ctxErr
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return nil, &commandError{command: args[0], stderr: redactString(strings.TrimSpace(stderr.String())), code: exitErr.ExitCode()}
+	} else if err != nil {
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
+
+func NewCLIRepository(path string) (*CLIRepository, error) {
+	return openCLIRepository(path, nil)
+}
+
+func NewCLIGitDirRepository(gitDir string) (*CLIRepository, error) {
+	return openCLIRepository("", []string{"--git-dir", gitDir})
+}
+
+func openCLIRepository(dir string, global []string) (*CLIRepository, error) {
+	out, err := runGit(context.Background(), dir, nil, nil, global, "rev-parse", "--absolute-git-dir")
+	var commandErr *commandError
+	if errors.As(err, &commandErr) && strings.Contains(commandErr.stderr, "not a git repository") {
+		return nil, git.ErrRepositoryNotExists
+	} else if err != nil {
+		return nil, err
+	}
+	return &CLIRepository{gitDir: strings.TrimSpace(string(out))}, nil
+}
+
+func NewCLITempBareRepository(initialCommit bool) (*CLIRepository, *string, error) {
+	name, err := randomRepositoryName()
+	if err != nil {
+		return nil, nil, err
+	}
+	remotePath := filepath.Join(os.TempDir(), name)
+	if _, err := runGit(context.Background(), "", nil, nil, nil, "init", "-q", "--bare", remotePath); err != nil {
+		return nil, nil, err
+	}
+	repository := CLIRepository{gitDir: remotePath}
+	if initialCommit {
+		_, err = repository.Commit("refs/heads/main", "initial", []byte(""), "Initial commit", WithHead())
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return &repository, &remotePath, nil
+}
+
+var _ Repository = &CLIRepository{}
@@ -0,0 +1,68 @@
+package repository_test
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/anselstetter/git-build-number/internal/repository"
+	"github.com/anselstetter/git-build-number/internal/repository/repositorytest"
+	"github.com/stretchr/testify/assert"
+)
+
+type backend struct {
+	name string
+	new  repositorytest.Factory
+}
+
+func backends(t *testing.T) []backend {
+	t.Helper()
+
+	list := []backend{
+		{name: "go-git in memory", new: func(t *testing.T, initialCommit bool) (repository.Repository, string) {
+			repo, _, err := repository.NewGitInMemoryRepository(initialCommit)
+			assert.NoError(t, err)
+			return repo, ""
+		}},
+		{name: "go-git", new: func(t *testing.T, initialCommit bool) (repository.Repository, string) {
+			repo, path, err := repository.NewGitTempBareRepository(initialCommit)
+			assert.NoError(t, err)
+			t.Cleanup(func() {
+				_ = os.RemoveAll(*path)
+			})
+			return repo, *path
+		}},
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Log("git not found, skipping the git backend")
+		return list
+	}
+	return append(list, backend{name: "git", new: func(t *testing.T, initialCommit bool) (repository.Repository, string) {
+		repo, path, err := repository.NewCLITempBareRepository(initialCommit)
+		assert.NoError(t, err)
+		t.Cleanup(func() {
+			_ = repo.Close()
+			_ = os.RemoveAll(*path)
+		})
+		return repo, *path
+	}})
+}
+
+func TestConformance(t *testing.T) {
+	for _, b := range backends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			t.Parallel()
+
+			repositorytest.Run(t, b.new)
+		})
+	}
+	t.Run("fake", func(t *testing.T) {
+		t.Parallel()
+
+		repositorytest.Run(t, func(t *testing.T, initialCommit bool) (repository.Repository, string) {
+			repo, _, err := repository.NewGitInMemoryRepository(initialCommit)
+			assert.NoError(t, err)
+			return repositorytest.NewFake(repo), ""
+		})
+	})
+}
@@ -1,29 +1,61 @@
 package repository
 
 import (
+	"bytes"
+	"context"
 	"crypto/rand"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
+	"maps"
+	"math"
 	"os"
 	"path"
 	"path/filepath"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/go-git/go-billy/v6/osfs"
 	"github.com/go-git/go-git/v6"
 	"github.com/go-git/go-git/v6/config"
 	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/go-git/go-git/v6/plumbing/cache"
 	"github.com/go-git/go-git/v6/plumbing/filemode"
+	"github.com/go-git/go-git/v6/plumbing/format/packfile"
 	"github.com/go-git/go-git/v6/plumbing/object"
+	"github.com/go-git/go-git/v6/plumbing/transport"
 	"github.com/go-git/go-git/v6/storage"
+	"github.com/go-git/go-git/v6/storage/filesystem"
+	"github.com/go-git/go-git/v6/storage/filesystem/dotgit"
 	"github.com/go-git/go-git/v6/storage/memory"
+	"github.com/go-git/go-git/v6/storage/transactional"
 )
 
 type GitRepository struct {
-	repo *git.Repository
+	repo             *git.Repository
+	report           ChangeFunc
+	auth             *Auth
+	transport        *transportOptions
+	unknownRevisions bool
+}
+
+type remoteOptions struct {
+	auth     transport.AuthMethod
+	caBundle []byte
+	insecure bool
+	proxy    transport.ProxyOptions
+}
+
+func (g *GitRepository) SetAuth(auth Auth) {
+	g.auth = &auth
+}
+
+func (g *GitRepository) SetUnknownRevisions(allow bool) {
+	g.unknownRevisions = allow
 }
 
 func (g *GitRepository) Head() (*Ref, error) {
@@ -39,7 +71,7 @@ func (g *GitRepository) Head() (*Ref, error) {
 	return &ref, nil
 }
 
-func (g *GitRepository) Refs(opts ...refsOption) ([]Ref, error) {
+func (g *GitRepository) Refs(opts ...RefsOption) ([]Ref, error) {
 	options := newRefsOptions(opts...)
 
 	references, err := g.repo.References()
@@ -47,8 +79,15 @@ func (g *GitRepository) Refs(opts ...refsOption) ([]Ref, error) {
 		return nil, mapError(err)
 	}
 	refs := []Ref{}
+	seen := map[plumbing.ReferenceName]bool{}
 	_ = references.ForEach(func(ref *plumbing.Reference) error {
-		if ref.Type() != plumbing.HashReference {
+		if seen[ref.Name()] {
+			return nil
+		}
+		seen[ref.Name()] = true
+
+		ref, err := g.repo.Storer.Reference(ref.Name())
+		if err != nil {
 			return nil
 		}
 		r := Ref{
@@ -59,22 +98,46 @@ func (g *GitRepository) Refs(opts ...refsOption) ([]Ref, error) {
 		if options.prefix != nil && !strings.HasPrefix(r.Path, *options.prefix) {
 			return nil
 		}
+		if ref.Type() == plumbing.SymbolicReference {
+			if !options.symbolic || ref.Name() == plumbing.HEAD {
+				return nil
+			}
+			r.Target = ref.Target().String()
+			r.Hash = ""
+			if resolved, err := g.repo.Reference(ref.Name(), true); err == nil {
+				r.Hash = resolved.Hash().String()
+			}
+		}
 		refs = append(refs, r)
 		return nil
 	})
+	slices.SortFunc(refs, func(a, b Ref) int {
+		return strings.Compare(a.Path, b.Path)
+	})
 	return refs, nil
 }
 
-func (g *GitRepository) RemoteRefs(remoteName string, opts ...refsOption) ([]Ref, error) {
+func (g *GitRepository) RemoteRefs(ctx context.Context, remoteName string, opts ...RefsOption) ([]Ref, error) {
 	options := newRefsOptions(opts...)
 
 	remote, err := g.repo.Remote(remoteName)
 	if err != nil {
 		return nil, mapError(err)
 	}
-	remoteRefs, err := remote.List(&git.ListOptions{})
+	remoteOpts, err := g.remoteOptions(remoteName)
 	if err != nil {
-		return nil, mapError(err)
+		return nil, err
+	}
+	remoteRefs, err := remote.ListContext(g.transport.context(ctx), &git.ListOptions{
+		Auth:            remoteOpts.auth,
+		CABundle:        remoteOpts.caBundle,
+		InsecureSkipTLS: remoteOpts.insecure,
+		ProxyOptions:    remoteOpts.proxy,
+	})
+	if errors.Is(err, transport.ErrEmptyRemoteRepository) {
+		return []Ref{}, nil
+	} else if err != nil {
+		return nil, g.remoteError(ctx, err)
 	}
 	refs := []Ref{}
 	for _, ref := range remoteRefs {
@@ -100,15 +163,19 @@ func (g *GitRepository) Content(refName string, fileName string) (*[]byte, error
 	if err != nil {
 		return nil, mapError(err)
 	}
-	tree, err := commit.Tree()
+	entries, err := g.treeEntries(commit.TreeHash)
 	if err != nil {
-		return nil, mapError(err)
+		return nil, err
+	}
+	index := slices.IndexFunc(entries, func(entry object.TreeEntry) bool { return entry.Name == fileName })
+	if index < 0 {
+		return nil, ErrFileNotFound
 	}
-	file, err := tree.File(fileName)
+	blob, err := g.repo.BlobObject(entries[index].Hash)
 	if err != nil {
 		return nil, mapError(err)
 	}
-	reader, err := file.Reader()
+	reader, err := blob.Reader()
 	if err != nil {
 		return nil, mapError(err)
 	}
@@ -121,7 +188,7 @@ func (g *GitRepository) Content(refName string, fileName string) (*[]byte, error
 	return &content, nil
 }
 
-func (g *GitRepository) Commits(refName string, opts ...commitsOption) ([]Commit, error) {
+func (g *GitRepository) Commits(refName string, opts ...CommitsOption) ([]Commit, error) {
 	options := newCommitsOptions(opts...)
 	errStop := errors.New("stop iteration")
 
@@ -133,26 +200,23 @@ func (g *GitRepository) Commits(refName string, opts ...commitsOption) ([]Commit
 	if err != nil {
 		return nil, mapError(err)
 	}
+	shallows, err := g.repo.Storer.Shallow()
+	if err != nil {
+		return nil, err
+	}
 	commits := []Commit{}
 
 	err = object.NewCommitPreorderIter(commit, nil, nil).ForEach(func(c *object.Commit) error {
-		headers := []Header{}
-		for _, header := range c.ExtraHeaders {
-			headers = append(headers, Header{Key: header.Key, Value: header.Value})
-		}
-		commit := Commit{
-			Hash:    c.Hash.String(),
-			Author:  Author{Name: c.Author.Name, Email: c.Author.Email},
-			When:    c.Author.When,
-			Message: c.Message,
-			Headers: headers,
-		}
+		commit := toCommit(c)
 		commits = append(commits, commit)
 
-		if options.headerKey != nil && slices.ContainsFunc(headers, func(header Header) bool { return header.Key == *options.headerKey }) {
+		if options.matches(commit) {
 			commits = []Commit{commit}
 			return errStop
 		}
+		if c.NumParents() > 0 && slices.Contains(shallows, c.Hash) {
+			return fmt.Errorf("%w: %s", ErrShallowHistory, refName)
+		}
 
 		return nil
 	})
@@ -161,17 +225,62 @@ func (g *GitRepository) Commits(refName string, opts ...commitsOption) ([]Commit
 	} else if err != nil {
 		return nil, mapError(err)
 	}
+	if options.filtered() {
+		return []Commit{}, nil
+	}
 	return commits, nil
 }
 
-func (g *GitRepository) Commit(refName, fileName string, content []byte, msg string, opts ...commitOption) (*Ref, error) {
+func (g *GitRepository) Lookup(hash string) (*Commit, error) {
+	c, err := g.repo.CommitObject(plumbing.NewHash(hash))
+	if err != nil {
+		return nil, mapError(err)
+	}
+	commit := toCommit(c)
+	return &commit, nil
+}
+
+func (g *GitRepository) Resolve(rev string) (string, error) {
+	hash, err := g.repo.ResolveRevision(plumbing.Revision(rev))
+	if err == nil {
+		return hash.String(), nil
+	}
+	format, formatErr := g.ObjectFormat()
+	if g.unknownRevisions && formatErr == nil && format.Valid(rev) {
+		return strings.ToLower(rev), nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrRevisionNotFound, rev)
+}
+
+func (g *GitRepository) Commit(refName, fileName string, content []byte, msg string, opts ...CommitOption) (*Ref, error) {
 	options := newCommitOptions(opts...)
 	store := g.repo.Storer
 
 	parents := []plumbing.Hash{}
-	ref, err := g.repo.Reference(plumbing.ReferenceName(refName), true)
+	old := ""
+	name, err := g.target(plumbing.ReferenceName(refName))
+	if err != nil {
+		return nil, err
+	}
+	entries := []object.TreeEntry{}
+	ref, err := g.repo.Reference(name, false)
 	if err == nil {
 		parents = append(parents, ref.Hash())
+		old = ref.Hash().String()
+
+		parent, err := g.repo.CommitObject(ref.Hash())
+		if err != nil {
+			return nil, mapError(err)
+		}
+		parentEntries, err := g.treeEntries(parent.TreeHash)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range parentEntries {
+			if entry.Name != fileName {
+				entries = append(entries, entry)
+			}
+		}
 	} else if !errors.Is(err, plumbing.ErrReferenceNotFound) {
 		return nil, err
 	}
@@ -180,17 +289,25 @@ func (g *GitRepository) Commit(refName, fileName string, content []byte, msg str
 	if err != nil {
 		return nil, err
 	}
+	entries = append(entries, object.TreeEntry{
+		Name: fileName,
+		Mode: filemode.Regular,
+		Hash: blobHash,
+	})
+	slices.SortFunc(entries, func(a, b object.TreeEntry) int {
+		return strings.Compare(a.Name, b.Name)
+	})
 	tree := &object.Tree{
-		Entries: []object.TreeEntry{{
-			Name: fileName,
-			Mode: filemode.Regular,
-			Hash: blobHash,
-		}},
+		Entries: entries,
 	}
 	treeHash, err := storeObject(store, tree)
 	if err != nil {
 		return nil, err
 	}
+	when, err := commitTime(options.when)
+	if err != nil {
+		return nil, err
+	}
 	extraHeaders := []object.ExtraHeader{}
 	for _, header := range options.headers {
 		extraHeaders = append(extraHeaders, object.ExtraHeader{Key: header.Key, Value: header.Value})
@@ -199,7 +316,7 @@ func (g *GitRepository) Commit(refName, fileName string, content []byte, msg str
 		Author: object.Signature{
 			Name:  options.author.Name,
 			Email: options.author.Email,
-			When:  time.Now(),
+			When:  when,
 		},
 		Message:      msg,
 		TreeHash:     treeHash,
@@ -210,10 +327,12 @@ func (g *GitRepository) Commit(refName, fileName string, content []byte, msg str
 	if err != nil {
 		return nil, err
 	}
-	newRef := plumbing.NewHashReference(plumbing.ReferenceName(refName), commitHash)
+	newRef := plumbing.NewHashReference(name, commitHash)
 	if err := store.SetReference(newRef); err != nil {
 		return nil, err
 	}
+	g.changed(Change{Ref: name.String(), Old: old, New: commitHash.String()})
+
 	if options.setHead {
 		headRef := plumbing.NewSymbolicReference(plumbing.HEAD, newRef.Name())
 		if err := store.SetReference(headRef); err != nil {
@@ -227,10 +346,58 @@ func (g *GitRepository) Commit(refName, fileName string, content []byte, msg str
 	}, nil
 }
 
+func (g *GitRepository) Update(refName string, hash string, oldHash string) error {
+	name, err := g.target(plumbing.ReferenceName(refName))
+	if err != nil {
+		return mapError(err)
+	}
+	old := ""
+
+	current, err := g.repo.Reference(name, false)
+	if err == nil {
+		old = current.Hash().String()
+	} else if !errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return mapError(err)
+	}
+	if _, err := g.repo.CommitObject(plumbing.NewHash(hash)); err != nil {
+		return mapError(err)
+	}
+	var expected *plumbing.Reference
+	if oldHash != "" {
+		expected = plumbing.NewHashReference(name, plumbing.NewHash(oldHash))
+	}
+	err = g.repo.Storer.CheckAndSetReference(plumbing.NewHashReference(name, plumbing.NewHash(hash)), expected)
+	if err != nil {
+		return mapError(err)
+	}
+	g.changed(Change{Ref: name.String(), Old: old, New: hash})
+
+	return nil
+}
+
+func (g *GitRepository) Alias(refName string, target string) error {
+	name := plumbing.ReferenceName(refName)
+	old := ""
+
+	current, err := g.repo.Reference(name, false)
+	if err == nil {
+		old = current.Target().String()
+	} else if !errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return mapError(err)
+	}
+	err = g.repo.Storer.SetReference(plumbing.NewSymbolicReference(name, plumbing.ReferenceName(target)))
+	if err != nil {
+		return mapError(err)
+	}
+	g.changed(Change{Ref: refName, Old: old, New: target})
+
+	return nil
+}
+
 func (g *GitRepository) Delete(refName string) error {
 	ref := plumbing.ReferenceName(refName)
 
-	_, err := g.repo.Reference(ref, false)
+	old, err := g.repo.Reference(ref, false)
 	if err != nil {
 		return mapError(err)
 	}
@@ -238,26 +405,156 @@ func (g *GitRepository) Delete(refName string) error {
 	if err != nil {
 		return mapError(err)
 	}
+	g.changed(Change{Ref: refName, Old: old.Hash().String()})
+
 	return nil
 }
 
-func (g *GitRepository) Fetch(refName string, remoteName string, force bool) error {
-	spec := fmt.Sprintf("%s:%s", refName, refName)
+func (g *GitRepository) DeleteRemote(ctx context.Context, refName string, remoteName string) error {
+	refs, err := g.RemoteRefs(ctx, remoteName, WithPrefix(refName))
+	if err != nil {
+		return err
+	}
+	index := slices.IndexFunc(refs, func(ref Ref) bool { return ref.Path == refName })
+	if index < 0 {
+		return ErrReferenceNotFound
+	}
+	if g.report != nil {
+		g.changed(Change{Remote: remoteName, Ref: refName, Old: refs[index].Hash})
+		return nil
+	}
+	remoteOpts, err := g.remoteOptions(remoteName)
+	if err != nil {
+		return err
+	}
+	err = g.repo.PushContext(g.transport.context(ctx), &git.PushOptions{
+		RemoteName:      remoteName,
+		Auth:            remoteOpts.auth,
+		CABundle:        remoteOpts.caBundle,
+		InsecureSkipTLS: remoteOpts.insecure,
+		ProxyOptions:    remoteOpts.proxy,
+		RefSpecs: []config.RefSpec{
+			config.RefSpec(fmt.Sprintf(":%s", refName)),
+		},
+		Force: true,
+	})
+	if err != nil && err != git.NoErrAlreadyUpToDate {
+		return g.remoteError(ctx, err)
+	}
+	return nil
+}
+
+func (g *GitRepository) Fetch(ctx context.Context, refName string, remoteName string, force bool, opts ...FetchOption) error {
+	options := newFetchOptions(opts...)
+	destination := refName
+	if options.destination != nil {
+		destination = *options.destination
+	}
+	spec := fmt.Sprintf("%s:%s", refName, destination)
 
-	err := g.repo.Fetch(&git.FetchOptions{
-		RemoteName: remoteName,
+	shallows, err := g.repo.Storer.Shallow()
+	if err != nil {
+		return err
+	}
+	depth := options.depth
+	if depth == 0 && len(shallows) > 0 {
+		depth = math.MaxInt32
+	}
+	remoteOpts, err := g.remoteOptions(remoteName)
+	if err != nil {
+		return err
+	}
+	fetchOpts := &git.FetchOptions{
+		RemoteName:      remoteName,
+		Auth:            remoteOpts.auth,
+		CABundle:        remoteOpts.caBundle,
+		InsecureSkipTLS: remoteOpts.insecure,
+		ProxyOptions:    remoteOpts.proxy,
 		RefSpecs: []config.RefSpec{
 			config.RefSpec(spec),
 		},
+		Depth: depth,
 		Force: force,
-	})
+	}
+	err = g.repo.FetchContext(g.transport.context(ctx), fetchOpts)
+	if errors.Is(err, packfile.ErrEmptyPackfile) && len(shallows) > 0 {
+		// A shallow repository always negotiates, and go-git answers with an
+		// empty pack when all objects are already present. Without the shallow
+		// commits the refs are updated from the local objects.
+		if err := g.repo.Storer.SetShallow(nil); err != nil {
+			return err
+		}
+		fetchOpts.Depth = 0
+		err = g.repo.FetchContext(g.transport.context(ctx), fetchOpts)
+		if err != nil && err != git.NoErrAlreadyUpToDate {
+			_ = g.repo.Storer.SetShallow(shallows)
+		}
+	}
 	if err != nil && err != git.NoErrAlreadyUpToDate {
-		return mapError(err)
+		return g.remoteError(ctx, err)
+	}
+	if depth > 0 {
+		return g.markShallow(destination, shallows, depth)
 	}
 	return nil
 }
 
-func (g *GitRepository) Mirror(refName string, remoteName string) error {
+func (g *GitRepository) markShallow(refName string, shallows []plumbing.Hash, depth int) error {
+	refs, err := g.Refs(WithPrefix(strings.TrimSuffix(refName, "*")))
+	if err != nil {
+		return err
+	}
+	shallow := map[plumbing.Hash]bool{}
+	for _, hash := range shallows {
+		shallow[hash] = true
+	}
+	type pendingCommit struct {
+		hash  plumbing.Hash
+		depth int
+	}
+	seen := map[plumbing.Hash]int{}
+	for _, ref := range refs {
+		if !strings.HasSuffix(refName, "*") && ref.Path != refName {
+			continue
+		}
+		pending := []pendingCommit{{hash: plumbing.NewHash(ref.Hash), depth: 1}}
+		for len(pending) > 0 {
+			current := pending[len(pending)-1]
+			pending = pending[:len(pending)-1]
+			if d, ok := seen[current.hash]; ok && d <= current.depth {
+				continue
+			}
+			seen[current.hash] = current.depth
+
+			commit, err := g.repo.CommitObject(current.hash)
+			if err != nil {
+				return mapError(err)
+			}
+			// go-git serves local remotes without cutting the history at the
+			// requested depth, so the boundary is recorded here like git does.
+			if current.depth >= depth && len(commit.ParentHashes) > 0 {
+				shallow[current.hash] = true
+				continue
+			}
+			delete(shallow, current.hash)
+			for _, parent := range commit.ParentHashes {
+				_, err := g.repo.Storer.EncodedObject(plumbing.CommitObject, parent)
+				if errors.Is(err, plumbing.ErrObjectNotFound) {
+					shallow[current.hash] = true
+					continue
+				} else if err != nil {
+					return err
+				}
+				pending = append(pending, pendingCommit{hash: parent, depth: current.depth + 1})
+			}
+		}
+	}
+	return g.repo.Storer.SetShallow(slices.SortedFunc(maps.Keys(shallow), func(a, b plumbing.Hash) int {
+		return strings.Compare(a.String(), b.String())
+	}))
+}
+
+func (g *GitRepository) Mirror(ctx context.Context, refName string, remoteName string) error {
 	spec := fmt.Sprintf("+%s*:%s*", refName, refName)
 	localRefs := map[string]bool{}
 
@@ -268,51 +565,77 @@ func (g *GitRepository) Mirror(refName string, remoteName string) error {
 	for _, ref := range refs {
 		localRefs[ref.Path] = true
 	}
-	err = g.repo.Push(&git.PushOptions{
-		RemoteName: remoteName,
+	if g.report != nil {
+		return reportPush(ctx, g, g.report, refName+"*", remoteName, true)
+	}
+	remoteOpts, err := g.remoteOptions(remoteName)
+	if err != nil {
+		return err
+	}
+	err = g.repo.PushContext(g.transport.context(ctx), &git.PushOptions{
+		RemoteName:      remoteName,
+		Auth:            remoteOpts.auth,
+		CABundle:        remoteOpts.caBundle,
+		InsecureSkipTLS: remoteOpts.insecure,
+		ProxyOptions:    remoteOpts.proxy,
 		RefSpecs: []config.RefSpec{
 			config.RefSpec(spec),
 		},
 		Force: true,
 	})
 	if err != nil && err != git.NoErrAlreadyUpToDate {
-		return mapError(err)
+		return g.remoteError(ctx, err)
 	}
-	remoteRefs, err := g.RemoteRefs(remoteName, WithPrefix(refName))
+	remoteRefs, err := g.RemoteRefs(ctx, remoteName, WithPrefix(refName))
 	if err != nil {
-		return mapError(err)
+		return g.remoteError(ctx, err)
 	}
 	for _, ref := range remoteRefs {
 		if !localRefs[ref.Path] {
 			delSpec := fmt.Sprintf(":%s", ref.Path)
 
-			err := g.repo.Push(&git.PushOptions{
-				RemoteName: remoteName,
+			err := g.repo.PushContext(g.transport.context(ctx), &git.PushOptions{
+				RemoteName:      remoteName,
+				Auth:            remoteOpts.auth,
+				CABundle:        remoteOpts.caBundle,
+				InsecureSkipTLS: remoteOpts.insecure,
+				ProxyOptions:    remoteOpts.proxy,
 				RefSpecs: []config.RefSpec{
 					config.RefSpec(delSpec),
 				},
 				Force: true,
 			})
 			if err != nil {
-				return fmt.Errorf("delete %s: %w", ref.Path, err)
+				return fmt.Errorf("delete %s: %w", ref.Path, g.remoteError(ctx, err))
 			}
 		}
 	}
 	return nil
 }
 
-func (g *GitRepository) Push(refName string, remoteName string, force bool) error {
+func (g *GitRepository) Push(ctx context.Context, refName string, remoteName string, force bool) error {
 	spec := fmt.Sprintf("%s:%s", refName, refName)
 
-	err := g.repo.Push(&git.PushOptions{
-		RemoteName: remoteName,
+	if g.report != nil {
+		return reportPush(ctx, g, g.report, refName, remoteName, false)
+	}
+	remoteOpts, err := g.remoteOptions(remoteName)
+	if err != nil {
+		return err
+	}
+	err = g.repo.PushContext(g.transport.context(ctx), &git.PushOptions{
+		RemoteName:      remoteName,
+		Auth:            remoteOpts.auth,
+		CABundle:        remoteOpts.caBundle,
+		InsecureSkipTLS: remoteOpts.insecure,
+		ProxyOptions:    remoteOpts.proxy,
 		RefSpecs: []config.RefSpec{
 			config.RefSpec(spec),
 		},
 		Force: force,
 	})
 	if err != nil && err != git.NoErrAlreadyUpToDate {
-		return mapError(err)
+		return g.remoteError(ctx, err)
 	}
 	return nil
 }
@@ -328,19 +651,245 @@ func (g *GitRepository) AddRemote(name string, urls ...string) error {
 	return nil
 }
 
+func (g *GitRepository) Config(section string) ([]ConfigOption, error) {
+	cfg, err := g.repo.ConfigScoped(config.SystemScope)
+	if err != nil {
+		return nil, err
+	}
+	options := []ConfigOption{}
+	if !cfg.Raw.HasSection(section) {
+		return options, nil
+	}
+	raw := cfg.Raw.Section(section)
+	for _, option := range raw.Options {
+		options = append(options, ConfigOption{Key: option.Key, Value: option.Value})
+	}
+	for _, subsection := range raw.Subsections {
+		for _, option := range subsection.Options {
+			options = append(options, ConfigOption{Subsection: subsection.Name, Key: option.Key, Value: option.Value})
+		}
+	}
+	return options, nil
+}
+
+func commitTime(when *time.Time) (time.Time, error) {
+	if when != nil {
+		return *when, nil
+	}
+	epoch, ok := os.LookupEnv("SOURCE_DATE_EPOCH")
+	if !ok || epoch == "" {
+		return time.Now(), nil
+	}
+	seconds, err := strconv.ParseInt(epoch, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %q", ErrInvalidSourceDateEpoch, epoch)
+	}
+	return time.Unix(seconds, 0).UTC(), nil
+}
+
+func (g *GitRepository) ObjectFormat() (ObjectFormat, error) {
+	cfg, err := g.repo.Config()
+	if err != nil {
+		return "", err
+	}
+	return objectFormat(cfg.Extensions.ObjectFormat), nil
+}
+
+func (g *GitRepository) DryRun(report ChangeFunc) error {
+	format, err := g.ObjectFormat()
+	if err != nil {
+		return err
+	}
+	temporal := memory.NewStorage(memory.WithObjectFormat(format.config()))
+	store := transactional.NewStorage(g.repo.Storer, temporal)
+
+	// transactional.Storage does not override RawObjectWriter, so objects
+	// parsed from a fetched pack would be written to the base storage.
+	// Drop dryRunStorage once go-git routes raw writes to the temporal storage.
+	repo, err := git.Open(&dryRunStorage{Storage: store, temporal: temporal}, nil)
+	if err != nil {
+		return err
+	}
+	g.repo = repo
+	g.report = report
+
+	return nil
+}
+
+type dryRunStorage struct {
+	transactional.Storage
+	temporal *memory.Storage
+}
+
+func (s *dryRunStorage) RawObjectWriter(typ plumbing.ObjectType, size int64) (io.WriteCloser, error) {
+	return s.temporal.RawObjectWriter(typ, size)
+}
+
+func (g *GitRepository) remoteOptions(remoteName string) (*remoteOptions, error) {
+	remote, err := g.repo.Remote(remoteName)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	opts := &remoteOptions{}
+	urls := remote.Config().URLs
+	if len(urls) == 0 {
+		return opts, nil
+	}
+	if g.auth != nil {
+		if opts.auth, err = g.auth.method(urls[0]); err != nil {
+			return nil, err
+		}
+	}
+	if g.transport != nil {
+		opts.caBundle = g.transport.caBundle
+		opts.insecure = g.transport.insecure
+		opts.proxy = g.transport.proxyOptions(urls[0])
+	}
+	return opts, nil
+}
+
+func (g *GitRepository) remoteError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return redact(mapError(err), g.auth.secrets()...)
+}
+
+func (g *GitRepository) target(name plumbing.ReferenceName) (plumbing.ReferenceName, error) {
+	for range 10 {
+		ref, err := g.repo.Storer.Reference(name)
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return name, nil
+		} else if err != nil {
+			return "", err
+		}
+		if ref.Type() != plumbing.SymbolicReference {
+			return name, nil
+		}
+		name = ref.Target()
+	}
+	return "", plumbing.ErrReferenceNotFound
+}
+
+func (g *GitRepository) changed(change Change) {
+	if g.report != nil {
+		g.report(change)
+	}
+}
+
+func reportPush(ctx context.Context, repo Repository, report ChangeFunc, refName string, remoteName string, prune bool) error {
+	prefix, wildcard := strings.CutSuffix(refName, "*")
+	matches := func(path string) bool {
+		if wildcard {
+			return strings.HasPrefix(path, prefix)
+		}
+		return path == refName
+	}
+	localRefs, err := repo.Refs(WithPrefix(prefix))
+	if err != nil {
+		return err
+	}
+	remoteRefs, err := repo.RemoteRefs(ctx, remoteName, WithPrefix(prefix))
+	if err != nil {
+		return err
+	}
+	remoteHashes := map[string]string{}
+	for _, ref := range remoteRefs {
+		if matches(ref.Path) {
+			remoteHashes[ref.Path] = ref.Hash
+		}
+	}
+	for _, ref := range localRefs {
+		if !matches(ref.Path) {
+			continue
+		}
+		old, ok := remoteHashes[ref.Path]
+		delete(remoteHashes, ref.Path)
+		if ok && old == ref.Hash {
+			continue
+		}
+		report(Change{Remote: remoteName, Ref: ref.Path, Old: old, New: ref.Hash})
+	}
+	if !prune {
+		return nil
+	}
+	for _, ref := range remoteRefs {
+		if old, ok := remoteHashes[ref.Path]; ok {
+			report(Change{Remote: remoteName, Ref: ref.Path, Old: old})
+		}
+	}
+	return nil
+}
+
+func toCommit(c *object.Commit) Commit {
+	headers := []Header{}
+	for _, header := range c.ExtraHeaders {
+		headers = append(headers, Header{Key: header.Key, Value: header.Value})
+	}
+	parents := []string{}
+	for _, parent := range c.ParentHashes {
+		parents = append(parents, parent.String())
+	}
+	return Commit{
+		Hash:    c.Hash.String(),
+		Author:  Author{Name: c.Author.Name, Email: c.Author.Email},
+		When:    c.Author.When,
+		Message: c.Message,
+		Headers: headers,
+		Parents: parents,
+	}
+}
+
+func (g *GitRepository) treeEntries(hash plumbing.Hash) ([]object.TreeEntry, error) {
+	obj, err := g.repo.Storer.EncodedObject(plumbing.TreeObject, hash)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	reader, err := obj.Reader()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	entries := []object.TreeEntry{}
+	for len(content) > 0 {
+		header, rest, ok := bytes.Cut(content, []byte{0})
+		mode, name, found := strings.Cut(string(header), " ")
+		if !ok || !found || len(rest) < hash.Size() {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidTree, hash)
+		}
+		fileMode, err := filemode.New(mode)
+		if err != nil {
+			return nil, err
+		}
+		entryHash, _ := plumbing.FromBytes(rest[:hash.Size()])
+		entries = append(entries, object.TreeEntry{Name: name, Mode: fileMode, Hash: entryHash})
+		content = rest[hash.Size():]
+	}
+	return entries, nil
+}
+
 func storeBlob(store storage.Storer, data []byte) (plumbing.Hash, error) {
-	obj := &plumbing.MemoryObject{}
+	obj := store.NewEncodedObject()
 	obj.SetType(plumbing.BlobObject)
 	obj.SetSize(int64(len(data)))
 
-	if _, err := obj.Write(data); err != nil {
+	writer, err := obj.Writer()
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	if _, err := writer.Write(data); err != nil {
 		return plumbing.ZeroHash, err
 	}
 	return store.SetEncodedObject(obj)
 }
 
 func storeObject(store storage.Storer, obj object.Object) (plumbing.Hash, error) {
-	mem := &plumbing.MemoryObject{}
+	mem := store.NewEncodedObject()
 	if err := obj.Encode(mem); err != nil {
 		return plumbing.ZeroHash, err
 	}
@@ -351,8 +900,22 @@ func mapError(err error) error {
 	switch {
 	case errors.Is(err, plumbing.ErrReferenceNotFound):
 		return ErrReferenceNotFound
+	case errors.Is(err, storage.ErrReferenceHasChanged):
+		return ErrReferenceChanged
+	case strings.HasPrefix(err.Error(), "non-fast-forward update"):
+		// go-git rejects pushes with an unwrapped fmt.Errorf, there is no
+		// sentinel to match. TestPush/non-fast-forward breaks if the message changes.
+		return ErrReferenceChanged
+	case errors.Is(err, git.ErrRemoteRefNotFound):
+		return ErrReferenceNotFound
+	case errors.Is(err, transport.ErrEmptyRemoteRepository):
+		return ErrReferenceNotFound
 	case errors.Is(err, git.ErrRemoteNotFound):
 		return ErrRemoteNotFound
+	case errors.Is(err, object.ErrFileNotFound):
+		return ErrFileNotFound
+	case errors.Is(err, plumbing.ErrObjectNotFound):
+		return ErrObjectNotFound
 	default:
 		return err
 	}
@@ -367,24 +930,77 @@ func randomRepositoryName() (string, error) {
 }
 
 func NewGitRepository(path string) (*GitRepository, error) {
-	repo, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{DetectDotGit: true})
+	return openGitRepository(path, true)
+}
+
+func NewGitDirRepository(gitDir string) (*GitRepository, error) {
+	return openGitRepository(gitDir, false)
+}
+
+func openGitRepository(path string, detect bool) (*GitRepository, error) {
+	repo, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{DetectDotGit: detect})
+	if errors.Is(err, git.ErrRepositoryNotExists) && detect {
+		repo, err = git.PlainOpenWithOptions(path, &git.PlainOpenOptions{})
+	}
 	if err != nil {
 		return nil, err
 	}
+	if store, ok := repo.Storer.(*filesystem.Storage); ok {
+		repo, err = openStorage(store.Filesystem().Root())
+		if err != nil {
+			return nil, err
+		}
+	}
 	repository := GitRepository{
 		repo: repo,
 	}
 	return &repository, nil
 }
 
+func openStorage(gitDir string) (*git.Repository, error) {
+	repositoryFs := osfs.New(gitDir)
+
+	content, err := os.ReadFile(filepath.Join(gitDir, "commondir"))
+	if err == nil {
+		commonDir := strings.TrimSpace(string(content))
+		if !filepath.IsAbs(commonDir) {
+			commonDir = filepath.Join(gitDir, commonDir)
+		}
+		repositoryFs = dotgit.NewRepositoryFilesystem(repositoryFs, osfs.New(commonDir))
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	cfg, err := filesystem.NewStorage(repositoryFs, cache.NewObjectLRUDefault()).Config()
+	if err != nil {
+		return nil, err
+	}
+	store := filesystem.NewStorageWithOptions(repositoryFs, cache.NewObjectLRUDefault(), filesystem.Options{
+		ObjectFormat: cfg.Extensions.ObjectFormat,
+	})
+	if objectFormat(cfg.Extensions.ObjectFormat) == SHA256 {
+		// go-git writes loose objects through objfile.Writer, which always
+		// hashes with SHA-1 and files SHA-256 objects under the wrong name.
+		// Drop looseObjectStorage once the writer honours the object format.
+		return git.Open(&looseObjectStorage{Storage: store, fs: repositoryFs}, nil)
+	}
+	return git.Open(store, nil)
+}
+
 func NewGitTempBareRepository(initialCommit bool) (*GitRepository, *string, error) {
+	return NewGitTempBareRepositoryWithFormat(SHA1, initialCommit)
+}
+
+func NewGitTempBareRepositoryWithFormat(format ObjectFormat, initialCommit bool) (*GitRepository, *string, error) {
 	name, err := randomRepositoryName()
 	if err != nil {
 		return nil, nil, err
 	}
 	tmpDir := os.TempDir()
 	remotePath := filepath.Join(tmpDir, name)
-	repo, err := git.PlainInit(remotePath, true)
+	if _, err := git.PlainInit(remotePath, true, git.WithObjectFormat(format.config())); err != nil {
+		return nil, nil, err
+	}
+	repo, err := openStorage(remotePath)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -401,9 +1017,13 @@ func NewGitTempBareRepository(initialCommit bool) (*GitRepository, *string, erro
 }
 
 func NewGitInMemoryRepository(initialCommit bool) (*GitRepository, *Ref, error) {
+	return NewGitInMemoryRepositoryWithFormat(SHA1, initialCommit)
+}
+
+func NewGitInMemoryRepositoryWithFormat(format ObjectFormat, initialCommit bool) (*GitRepository, *Ref, error) {
 	var ref *Ref
 
-	repo, err := git.Init(memory.NewStorage(), nil)
+	repo, err := git.Init(memory.NewStorage(memory.WithObjectFormat(format.config())), git.WithObjectFormat(format.config()))
 	if err != nil {
 		return nil, ref, err
 	}
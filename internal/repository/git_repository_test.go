@@ -2,7 +2,10 @@ package repository_test
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/anselstetter/git-build-number/internal/repository"
 	"github.com/stretchr/testify/assert"
@@ -60,6 +63,71 @@ func TestCommit(t *testing.T) {
 	assert.Equal(t, head, ref)
 }
 
+func TestCommitKeepsFiles(t *testing.T) {
+	repo, _, err := repository.NewGitInMemoryRepository(true)
+	assert.NoError(t, err)
+
+	_, _ = repo.Commit("refs/custom/test", "a", []byte("1"), "commit")
+	_, _ = repo.Commit("refs/custom/test", "b", []byte("2"), "commit")
+	_, _ = repo.Commit("refs/custom/test", "a", []byte("3"), "commit")
+
+	a, err := repo.Content("refs/custom/test", "a")
+	assert.NoError(t, err)
+	assert.Equal(t, "3", string(*a))
+
+	b, err := repo.Content("refs/custom/test", "b")
+	assert.NoError(t, err)
+	assert.Equal(t, "2", string(*b))
+
+	_, err = repo.Content("refs/custom/test", "c")
+	assert.ErrorIs(t, err, repository.ErrFileNotFound)
+}
+
+func TestCommitTime(t *testing.T) {
+	t.Run("with time", func(t *testing.T) {
+		repo, _, err := repository.NewGitInMemoryRepository(false)
+		assert.NoError(t, err)
+
+		when := time.Unix(1700000000, 0).UTC()
+
+		_, err = repo.Commit("refs/custom/test", "test", []byte(""), "commit", repository.WithTime(when))
+		assert.NoError(t, err)
+
+		commits, err := repo.Commits("refs/custom/test")
+		assert.NoError(t, err)
+		assert.True(t, when.Equal(commits[0].When))
+	})
+	t.Run("SOURCE_DATE_EPOCH", func(t *testing.T) {
+		t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+
+		hashes := []string{}
+		for range 2 {
+			repo, _, err := repository.NewGitInMemoryRepository(true)
+			assert.NoError(t, err)
+
+			ref, err := repo.Commit("refs/custom/test", "test", []byte("content"), "commit")
+			assert.NoError(t, err)
+
+			commits, err := repo.Commits("refs/custom/test")
+			assert.NoError(t, err)
+			assert.Equal(t, int64(1700000000), commits[0].When.Unix())
+
+			hashes = append(hashes, ref.Hash)
+		}
+		assert.Equal(t, hashes[0], hashes[1])
+	})
+	t.Run("invalid SOURCE_DATE_EPOCH", func(t *testing.T) {
+		t.Setenv("SOURCE_DATE_EPOCH", "yesterday")
+
+		repo, _, err := repository.NewGitInMemoryRepository(false)
+		assert.NoError(t, err)
+
+		ref, err := repo.Commit("refs/custom/test", "test", []byte(""), "commit")
+		assert.Nil(t, ref)
+		assert.ErrorIs(t, err, repository.ErrInvalidSourceDateEpoch)
+	})
+}
+
 func TestCommits(t *testing.T) {
 	t.Run("without header keys", func(t *testing.T) {
 		t.Parallel()
@@ -121,6 +189,28 @@ func TestCommits(t *testing.T) {
 		assert.Equal(t, commits[0].Author, repository.Author{Name: "name", Email: "mail"})
 		assert.Equal(t, commits[0].Message, "commit")
 		assert.Equal(t, commits[0].Headers, []repository.Header{{Key: "key2", Value: "value2"}})
+
+		commits, err = repo.Commits("refs/custom/test", repository.WithHeaderKey("missing"))
+		assert.NoError(t, err)
+		assert.Empty(t, commits)
+	})
+	t.Run("with header value", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, err := repository.NewGitInMemoryRepository(false)
+		assert.NoError(t, err)
+
+		ref1, _ := repo.Commit("refs/custom/test", "test", []byte("1"), "commit", repository.WithHeaders([]repository.Header{{Key: "1", Value: "a"}}))
+		_, _ = repo.Commit("refs/custom/test", "test", []byte("2"), "commit", repository.WithHeaders([]repository.Header{{Key: "2", Value: "b"}}))
+
+		commits, err := repo.Commits("refs/custom/test", repository.WithHeaderValue("a"))
+		assert.NoError(t, err)
+		assert.Len(t, commits, 1)
+		assert.Equal(t, ref1.Hash, commits[0].Hash)
+
+		commits, err = repo.Commits("refs/custom/test", repository.WithHeaderValue("c"))
+		assert.NoError(t, err)
+		assert.Empty(t, commits)
 	})
 }
 
@@ -159,7 +249,7 @@ func TestRemoteRefs(t *testing.T) {
 	repo, _, err := repository.NewGitInMemoryRepository(true)
 	assert.NoError(t, err)
 
-	refs, err := repo.RemoteRefs("origin")
+	refs, err := repo.RemoteRefs(t.Context(), "origin")
 	assert.ErrorIs(t, err, repository.ErrRemoteNotFound)
 
 	expected := []repository.Ref(nil)
@@ -198,6 +288,38 @@ func TestDelete(t *testing.T) {
 	assert.Equal(t, len(refs2), 0)
 }
 
+func TestAlias(t *testing.T) {
+	repo, _, err := repository.NewGitInMemoryRepository(true)
+	assert.NoError(t, err)
+
+	target, _ := repo.Commit("refs/custom/target", "file", []byte("1"), "commit")
+
+	err = repo.Alias("refs/custom/alias", "refs/custom/target")
+	assert.NoError(t, err)
+
+	refs, _ := repo.Refs(repository.WithPrefix("refs/custom/"))
+	assert.Equal(t, []repository.Ref{*target}, refs)
+
+	refs, _ = repo.Refs(repository.WithPrefix("refs/custom/"), repository.WithSymbolic())
+	assert.Equal(t, []repository.Ref{
+		{Path: "refs/custom/alias", Name: "alias", Hash: target.Hash, Target: "refs/custom/target"},
+		*target,
+	}, refs)
+
+	commit, err := repo.Commit("refs/custom/alias", "file", []byte("2"), "commit")
+	assert.NoError(t, err)
+	assert.Equal(t, "refs/custom/target", commit.Path)
+
+	content, _ := repo.Content("refs/custom/alias", "file")
+	assert.Equal(t, "2", string(*content))
+
+	err = repo.Delete("refs/custom/alias")
+	assert.NoError(t, err)
+
+	refs, _ = repo.Refs(repository.WithPrefix("refs/custom/"), repository.WithSymbolic())
+	assert.Equal(t, []repository.Ref{*commit}, refs)
+}
+
 func TestAddRemote(t *testing.T) {
 	repo, _, err := repository.NewGitInMemoryRepository(false)
 	assert.NoError(t, err)
@@ -206,6 +328,38 @@ func TestAddRemote(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestConfig(t *testing.T) {
+	t.Run("without section", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, err := repository.NewGitInMemoryRepository(true)
+		assert.NoError(t, err)
+
+		options, err := repo.Config("build-number")
+		assert.NoError(t, err)
+		assert.Empty(t, options)
+	})
+	t.Run("with section", func(t *testing.T) {
+		t.Parallel()
+
+		repo, path, err := repository.NewGitTempBareRepository(true)
+		assert.NoError(t, err)
+		t.Cleanup(func() {
+			_ = os.RemoveAll(*path)
+		})
+		file, _ := os.OpenFile(filepath.Join(*path, "config"), os.O_APPEND|os.O_WRONLY, 0o644)
+		_, _ = file.WriteString("[custom]\n\tkey = value\n[custom \"sub\"]\n\tother = 1\n")
+		_ = file.Close()
+
+		options, err := repo.Config("custom")
+		assert.NoError(t, err)
+		assert.Equal(t, []repository.ConfigOption{
+			{Key: "key", Value: "value"},
+			{Subsection: "sub", Key: "other", Value: "1"},
+		}, options)
+	})
+}
+
 func TestPush(t *testing.T) {
 	t.Run("without remote", func(t *testing.T) {
 		t.Parallel()
@@ -213,7 +367,7 @@ func TestPush(t *testing.T) {
 		repo, _, err := repository.NewGitInMemoryRepository(true)
 		assert.NoError(t, err)
 
-		err = repo.Push("refs/heads/main", "origin", false)
+		err = repo.Push(t.Context(), "refs/heads/main", "origin", false)
 		assert.ErrorIs(t, err, repository.ErrRemoteNotFound)
 	})
 	t.Run("with remote", func(t *testing.T) {
@@ -223,12 +377,64 @@ func TestPush(t *testing.T) {
 		remote := addRemote(t, "origin", false, repo)
 		assert.NoError(t, err)
 
-		err = repo.Push("refs/heads/main", "origin", false)
+		err = repo.Push(t.Context(), "refs/heads/main", "origin", false)
 		assert.NoError(t, err)
 
 		refs, _ := remote.Refs()
 		assert.Equal(t, *ref, refs[0])
 	})
+	t.Run("non-fast-forward", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, err := repository.NewGitInMemoryRepository(false)
+		assert.NoError(t, err)
+		remote := addRemote(t, "origin", false, repo)
+		_, _ = remote.Commit("refs/custom/test", "test", []byte("remote"), "commit")
+		_, _ = repo.Commit("refs/custom/test", "test", []byte("local"), "commit")
+
+		err = repo.Push(t.Context(), "refs/custom/test", "origin", false)
+		assert.ErrorIs(t, err, repository.ErrReferenceChanged)
+
+		err = repo.Push(t.Context(), "refs/custom/test", "origin", true)
+		assert.NoError(t, err)
+	})
+}
+
+func TestDeleteRemote(t *testing.T) {
+	t.Run("without remote", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, err := repository.NewGitInMemoryRepository(true)
+		assert.NoError(t, err)
+
+		err = repo.DeleteRemote(t.Context(), "refs/heads/main", "origin")
+		assert.ErrorIs(t, err, repository.ErrRemoteNotFound)
+	})
+	t.Run("without reference", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, err := repository.NewGitInMemoryRepository(true)
+		_ = addRemote(t, "origin", true, repo)
+		assert.NoError(t, err)
+
+		err = repo.DeleteRemote(t.Context(), "refs/custom/main", "origin")
+		assert.ErrorIs(t, err, repository.ErrReferenceNotFound)
+	})
+	t.Run("with remote", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, err := repository.NewGitInMemoryRepository(true)
+		remote := addRemote(t, "origin", false, repo)
+		assert.NoError(t, err)
+
+		_ = repo.Push(t.Context(), "refs/heads/main", "origin", false)
+
+		err = repo.DeleteRemote(t.Context(), "refs/heads/main", "origin")
+		assert.NoError(t, err)
+
+		refs, _ := remote.Refs(repository.WithPrefix("refs/heads/"))
+		assert.Empty(t, refs)
+	})
 }
 
 func TestMirror(t *testing.T) {
@@ -238,7 +444,7 @@ func TestMirror(t *testing.T) {
 		repo, _, err := repository.NewGitInMemoryRepository(true)
 		assert.NoError(t, err)
 
-		err = repo.Mirror("refs/heads/main", "origin")
+		err = repo.Mirror(t.Context(), "refs/heads/main", "origin")
 		assert.ErrorIs(t, err, repository.ErrRemoteNotFound)
 	})
 	t.Run("with remote", func(t *testing.T) {
@@ -248,7 +454,7 @@ func TestMirror(t *testing.T) {
 		remote := addRemote(t, "origin", false, repo)
 		assert.NoError(t, err)
 
-		err = repo.Mirror("refs/heads/main", "origin")
+		err = repo.Mirror(t.Context(), "refs/heads/main", "origin")
 		assert.NoError(t, err)
 
 		refs, _ := remote.Refs()
@@ -261,7 +467,7 @@ func TestFetch(t *testing.T) {
 		repo, _, err := repository.NewGitInMemoryRepository(true)
 		assert.NoError(t, err)
 
-		err = repo.Fetch("refs/heads/main", "origin", false)
+		err = repo.Fetch(t.Context(), "refs/heads/main", "origin", false)
 		assert.ErrorIs(t, err, repository.ErrRemoteNotFound)
 	})
 	t.Run("with remote", func(t *testing.T) {
@@ -271,7 +477,7 @@ func TestFetch(t *testing.T) {
 		remote := addRemote(t, "origin", true, repo)
 		assert.NoError(t, err)
 
-		err = repo.Fetch("refs/heads/main", "origin", false)
+		err = repo.Fetch(t.Context(), "refs/heads/main", "origin", false)
 		assert.NoError(t, err)
 
 		remoteRefs, _ := remote.Refs()
@@ -279,4 +485,331 @@ func TestFetch(t *testing.T) {
 
 		assert.Equal(t, remoteRefs, localRefs)
 	})
+	t.Run("with destination", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, err := repository.NewGitInMemoryRepository(false)
+		remote := addRemote(t, "origin", true, repo)
+		assert.NoError(t, err)
+
+		err = repo.Fetch(t.Context(), "refs/heads/main", "origin", true, repository.WithDestination("refs/remotes/origin/main"))
+		assert.NoError(t, err)
+
+		remoteRefs, _ := remote.Refs()
+		localRefs, _ := repo.Refs()
+
+		assert.Equal(t, []repository.Ref{{Path: "refs/remotes/origin/main", Name: "main", Hash: remoteRefs[0].Hash}}, localRefs)
+	})
+	t.Run("missing remote ref", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, err := repository.NewGitInMemoryRepository(false)
+		_ = addRemote(t, "origin", true, repo)
+		assert.NoError(t, err)
+
+		err = repo.Fetch(t.Context(), "refs/heads/missing", "origin", false)
+		assert.ErrorIs(t, err, repository.ErrReferenceNotFound)
+	})
+}
+
+func TestDryRun(t *testing.T) {
+	repo, _, err := repository.NewGitInMemoryRepository(true)
+	assert.NoError(t, err)
+
+	ref, err := repo.Commit("refs/custom/keep", "test", []byte("test"), "commit")
+	assert.NoError(t, err)
+
+	changes := []repository.Change{}
+	err = repo.DryRun(func(change repository.Change) {
+		changes = append(changes, change)
+	})
+	assert.NoError(t, err)
+
+	updated, err := repo.Commit("refs/custom/keep", "test", []byte("updated"), "commit")
+	assert.NoError(t, err)
+
+	created, err := repo.Commit("refs/custom/new", "test", []byte("new"), "commit")
+	assert.NoError(t, err)
+
+	err = repo.Delete("refs/custom/new")
+	assert.NoError(t, err)
+
+	refs, err := repo.Refs(repository.WithPrefix("refs/custom"))
+	assert.NoError(t, err)
+	assert.Equal(t, []repository.Ref{*updated}, refs)
+
+	expected := []repository.Change{
+		{Ref: "refs/custom/keep", Old: ref.Hash, New: updated.Hash},
+		{Ref: "refs/custom/new", New: created.Hash},
+		{Ref: "refs/custom/new", Old: created.Hash},
+	}
+	assert.Equal(t, expected, changes)
+	assert.Equal(t, []string{"update", "create", "delete"}, []string{changes[0].Action(), changes[1].Action(), changes[2].Action()})
+}
+
+func TestUpdate(t *testing.T) {
+	t.Run("without old hash", func(t *testing.T) {
+		t.Parallel()
+
+		repo, head, err := repository.NewGitInMemoryRepository(true)
+		assert.NoError(t, err)
+
+		err = repo.Update("refs/custom/test", head.Hash, "")
+		assert.NoError(t, err)
+
+		refs, _ := repo.Refs(repository.WithPrefix("refs/custom/"))
+		assert.Equal(t, []repository.Ref{{Path: "refs/custom/test", Name: "test", Hash: head.Hash}}, refs)
+	})
+	t.Run("with old hash", func(t *testing.T) {
+		t.Parallel()
+
+		repo, head, err := repository.NewGitInMemoryRepository(true)
+		assert.NoError(t, err)
+
+		ref, err := repo.Commit("refs/custom/test", "test", []byte("test"), "commit")
+		assert.NoError(t, err)
+
+		err = repo.Update("refs/custom/test", head.Hash, head.Hash)
+		assert.ErrorIs(t, err, repository.ErrReferenceChanged)
+
+		err = repo.Update("refs/custom/test", head.Hash, ref.Hash)
+		assert.NoError(t, err)
+	})
+	t.Run("missing object", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, err := repository.NewGitInMemoryRepository(true)
+		assert.NoError(t, err)
+
+		err = repo.Update("refs/custom/test", "0123456789012345678901234567890123456789", "")
+		assert.Error(t, err)
+	})
+}
+
+func TestResolve(t *testing.T) {
+	repo, initial, err := repository.NewGitInMemoryRepository(true)
+	assert.NoError(t, err)
+
+	head, err := repo.Commit("refs/heads/main", "next", []byte("next"), "Next commit", repository.WithHead())
+	assert.NoError(t, err)
+	assert.NoError(t, repo.Update("refs/tags/v1.0.0", initial.Hash, ""))
+
+	for _, tc := range []struct {
+		rev  string
+		hash string
+	}{
+		{rev: "HEAD", hash: head.Hash},
+		{rev: "main", hash: head.Hash},
+		{rev: "HEAD~1", hash: initial.Hash},
+		{rev: "v1.0.0", hash: initial.Hash},
+		{rev: head.Hash[:7], hash: head.Hash},
+	} {
+		t.Run(tc.rev, func(t *testing.T) {
+			t.Parallel()
+
+			hash, err := repo.Resolve(tc.rev)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.hash, hash)
+		})
+	}
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		hash, err := repo.Resolve("missing")
+		assert.ErrorIs(t, err, repository.ErrRevisionNotFound)
+		assert.Equal(t, "", hash)
+
+		hash, err = repo.Resolve(strings.Repeat("a", 39))
+		assert.ErrorIs(t, err, repository.ErrRevisionNotFound)
+		assert.Equal(t, "", hash)
+
+		hash, err = repo.Resolve(strings.Repeat("a", 40))
+		assert.ErrorIs(t, err, repository.ErrRevisionNotFound)
+		assert.Equal(t, "", hash)
+	})
+	t.Run("unknown revisions", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _, _ := repository.NewGitInMemoryRepository(false)
+		repo.SetUnknownRevisions(true)
+
+		hash, err := repo.Resolve(strings.Repeat("A", 40))
+		assert.NoError(t, err)
+		assert.Equal(t, strings.Repeat("a", 40), hash)
+
+		hash, err = repo.Resolve("missing")
+		assert.ErrorIs(t, err, repository.ErrRevisionNotFound)
+		assert.Equal(t, "", hash)
+	})
+}
+
+func TestNewGitRepository(t *testing.T) {
+	t.Run("bare", func(t *testing.T) {
+		t.Parallel()
+
+		_, path, err := repository.NewGitTempBareRepository(true)
+		assert.NoError(t, err)
+		t.Cleanup(func() {
+			_ = os.RemoveAll(*path)
+		})
+
+		repo, err := repository.NewGitRepository(*path)
+		assert.NoError(t, err)
+
+		head, err := repo.Head()
+		assert.NoError(t, err)
+		assert.Equal(t, "refs/heads/main", head.Path)
+	})
+	t.Run("git dir", func(t *testing.T) {
+		t.Parallel()
+
+		_, path, err := repository.NewGitTempBareRepository(true)
+		assert.NoError(t, err)
+		t.Cleanup(func() {
+			_ = os.RemoveAll(*path)
+		})
+
+		repo, err := repository.NewGitDirRepository(*path)
+		assert.NoError(t, err)
+
+		_, err = repo.Head()
+		assert.NoError(t, err)
+	})
+	t.Run("linked worktree", func(t *testing.T) {
+		t.Parallel()
+
+		common, path, err := repository.NewGitTempBareRepository(true)
+		assert.NoError(t, err)
+		t.Cleanup(func() {
+			_ = os.RemoveAll(*path)
+		})
+		gitDir := filepath.Join(*path, "worktrees", "linked")
+		worktree := t.TempDir()
+		assert.NoError(t, os.MkdirAll(gitDir, 0o755))
+		assert.NoError(t, os.WriteFile(filepath.Join(gitDir, "HEAD"), []byte("ref: refs/heads/main\n"), 0o644))
+		assert.NoError(t, os.WriteFile(filepath.Join(gitDir, "commondir"), []byte("../..\n"), 0o644))
+		assert.NoError(t, os.WriteFile(filepath.Join(gitDir, "gitdir"), []byte(filepath.Join(worktree, ".git")+"\n"), 0o644))
+		assert.NoError(t, os.WriteFile(filepath.Join(worktree, ".git"), []byte("gitdir: "+gitDir+"\n"), 0o644))
+
+		repo, err := repository.NewGitRepository(worktree)
+		assert.NoError(t, err)
+
+		head, err := repo.Head()
+		assert.NoError(t, err)
+		assert.Equal(t, "refs/heads/main", head.Path)
+
+		ref, err := repo.Commit("refs/custom/test", "test", []byte("test"), "commit")
+		assert.NoError(t, err)
+
+		refs, err := common.Refs(repository.WithPrefix("refs/custom/"))
+		assert.NoError(t, err)
+		assert.Equal(t, []repository.Ref{*ref}, refs)
+
+		content, err := common.Content("refs/custom/test", "test")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("test"), *content)
+	})
+	t.Run("not a repository", func(t *testing.T) {
+		t.Parallel()
+
+		repo, err := repository.NewGitRepository(t.TempDir())
+		assert.Error(t, err)
+		assert.Nil(t, repo)
+	})
+}
+
+func TestObjectFormat(t *testing.T) {
+	for _, format := range []repository.ObjectFormat{repository.SHA1, repository.SHA256} {
+		t.Run(string(format)+" in memory", func(t *testing.T) {
+			t.Parallel()
+
+			repo, head, err := repository.NewGitInMemoryRepositoryWithFormat(format, true)
+			assert.NoError(t, err)
+
+			testObjectFormat(t, repo, head, format)
+		})
+		t.Run(string(format)+" on disk", func(t *testing.T) {
+			t.Parallel()
+
+			repo, path, err := repository.NewGitTempBareRepositoryWithFormat(format, true)
+			assert.NoError(t, err)
+			t.Cleanup(func() {
+				_ = os.RemoveAll(*path)
+			})
+			head, err := repo.Head()
+			assert.NoError(t, err)
+
+			testObjectFormat(t, repo, head, format)
+
+			reopened, err := repository.NewGitRepository(*path)
+			assert.NoError(t, err)
+
+			content, err := reopened.Content("refs/custom/test", "second")
+			assert.NoError(t, err)
+			assert.Equal(t, []byte("second"), *content)
+		})
+	}
+}
+
+func testObjectFormat(t *testing.T, repo repository.Repository, head *repository.Ref, format repository.ObjectFormat) {
+	t.Helper()
+
+	objectFormat, err := repo.ObjectFormat()
+	assert.NoError(t, err)
+	assert.Equal(t, format, objectFormat)
+	assert.Len(t, head.Hash, format.HexSize())
+	assert.True(t, format.Valid(head.Hash))
+
+	_, err = repo.Commit("refs/custom/test", "first", []byte("first"), "commit")
+	assert.NoError(t, err)
+	ref, err := repo.Commit("refs/custom/test", "second", []byte("second"), "commit")
+	assert.NoError(t, err)
+	assert.Len(t, ref.Hash, format.HexSize())
+
+	content, err := repo.Content("refs/custom/test", "first")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("first"), *content)
+
+	_, err = repo.Content("refs/custom/test", "missing")
+	assert.ErrorIs(t, err, repository.ErrFileNotFound)
+
+	hash, err := repo.Resolve(ref.Hash[:7])
+	assert.NoError(t, err)
+	assert.Equal(t, ref.Hash, hash)
+}
+
+func TestObjectFormatValid(t *testing.T) {
+	sha1 := "0123456789abcdef0123456789abcdef01234567"
+	sha256 := "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+	assert.True(t, repository.SHA1.Valid(sha1))
+	assert.False(t, repository.SHA1.Valid(sha256))
+	assert.True(t, repository.SHA256.Valid(sha256))
+	assert.False(t, repository.SHA256.Valid(sha1))
+	assert.False(t, repository.SHA1.Valid("xyz3456789abcdef0123456789abcdef01234567"))
+}
+
+func TestCommitsShallow(t *testing.T) {
+	remote, path, err := repository.NewGitTempBareRepository(false)
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		_ = os.RemoveAll(*path)
+	})
+	_, _ = remote.Commit("refs/custom/test", "test", []byte("1"), "first", repository.WithHeaders([]repository.Header{{Key: "1", Value: "a"}}))
+	ref, _ := remote.Commit("refs/custom/test", "test", []byte("2"), "second", repository.WithHeaders([]repository.Header{{Key: "2", Value: "b"}}))
+	assert.NoError(t, os.WriteFile(filepath.Join(*path, "shallow"), []byte(ref.Hash+"\n"), 0o644))
+
+	repo, err := repository.NewGitRepository(*path)
+	assert.NoError(t, err)
+
+	commits, err := repo.Commits("refs/custom/test", repository.WithHeaderKey("2"))
+	assert.NoError(t, err)
+	assert.Len(t, commits, 1)
+
+	commits, err = repo.Commits("refs/custom/test", repository.WithHeaderKey("1"))
+	assert.ErrorIs(t, err, repository.ErrShallowHistory)
+	assert.Nil(t, commits)
+
+	_, err = repo.Commits("refs/custom/test")
+	assert.ErrorIs(t, err, repository.ErrShallowHistory)
 }
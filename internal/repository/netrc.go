@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+type netrcEntry struct {
+	machine  string
+	login    string
+	password string
+}
+
+func netrcLookup(path string, host string) (*netrcEntry, error) {
+	if path == "" {
+		return nil, nil
+	}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var fallback *netrcEntry
+	for _, entry := range parseNetrc(string(content)) {
+		if entry.machine == "" && fallback == nil {
+			fallback = &entry
+		}
+		if entry.machine != "" && strings.EqualFold(entry.machine, host) {
+			return &entry, nil
+		}
+	}
+	return fallback, nil
+}
+
+func parseNetrc(content string) []netrcEntry {
+	tokens := []string{}
+	macro := false
+	for line := range strings.Lines(content) {
+		if macro {
+			macro = strings.TrimSpace(line) != ""
+			continue
+		}
+		fields := strings.Fields(line)
+		for i, field := range fields {
+			if strings.HasPrefix(field, "#") {
+				fields = fields[:i]
+				break
+			}
+			if field == "macdef" {
+				fields = fields[:i]
+				macro = true
+				break
+			}
+		}
+		tokens = append(tokens, fields...)
+	}
+
+	entries := []netrcEntry{}
+	for i := 0; i < len(tokens); i++ {
+		value := func() string {
+			if i+1 >= len(tokens) {
+				return ""
+			}
+			i++
+			return tokens[i]
+		}
+		switch tokens[i] {
+		case "machine":
+			entries = append(entries, netrcEntry{machine: value()})
+		case "default":
+			entries = append(entries, netrcEntry{})
+		case "login":
+			login := value()
+			if len(entries) > 0 {
+				entries[len(entries)-1].login = login
+			}
+		case "password":
+			password := value()
+			if len(entries) > 0 {
+				entries[len(entries)-1].password = password
+			}
+		case "account":
+			value()
+		}
+	}
+	return entries
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+
+	"github.com/go-git/go-billy/v6"
+	"github.com/go-git/go-git/v6/plumbing"
+	formatcfg "github.com/go-git/go-git/v6/plumbing/format/config"
+	"github.com/go-git/go-git/v6/storage/filesystem"
+)
+
+type ObjectFormat string
+
+const (
+	SHA1   ObjectFormat = "sha1"
+	SHA256 ObjectFormat = "sha256"
+)
+
+func (f ObjectFormat) HexSize() int {
+	if f == SHA256 {
+		return 64
+	}
+	return 40
+}
+
+func (f ObjectFormat) Valid(hash string) bool {
+	if len(hash) != f.HexSize() {
+		return false
+	}
+	_, ok := plumbing.FromHex(hash)
+	return ok
+}
+
+func objectFormat(format formatcfg.ObjectFormat) ObjectFormat {
+	if format == formatcfg.SHA256 {
+		return SHA256
+	}
+	return SHA1
+}
+
+func (f ObjectFormat) config() formatcfg.ObjectFormat {
+	if f == SHA256 {
+		return formatcfg.SHA256
+	}
+	return formatcfg.SHA1
+}
+
+type looseObjectStorage struct {
+	*filesystem.Storage
+	fs billy.Filesystem
+}
+
+func (s *looseObjectStorage) SetEncodedObject(obj plumbing.EncodedObject) (plumbing.Hash, error) {
+	hash := obj.Hash()
+	if s.HasEncodedObject(hash) == nil {
+		return hash, nil
+	}
+	reader, err := obj.Reader()
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	defer reader.Close()
+
+	content := bytes.Buffer{}
+	writer := zlib.NewWriter(&content)
+	if _, err := fmt.Fprintf(writer, "%s %d\x00", obj.Type(), obj.Size()); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	if _, err := io.Copy(writer, reader); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	if err := writer.Close(); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	hex := hash.String()
+	dir := s.fs.Join("objects", hex[:2])
+	if err := s.fs.MkdirAll(dir, 0o755); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	tmp, err := s.fs.TempFile(dir, "tmp_obj_")
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	if _, err := tmp.Write(content.Bytes()); err != nil {
+		_ = tmp.Close()
+		return plumbing.ZeroHash, err
+	}
+	if err := tmp.Close(); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return hash, s.fs.Rename(tmp.Name(), s.fs.Join(dir, hex[2:]))
+}
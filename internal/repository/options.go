@@ -1,14 +1,21 @@
 package repository
 
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
 type commitOptions struct {
 	author  Author
 	setHead bool
 	headers []Header
+	when    *time.Time
 }
 
-type commitOption func(opts *commitOptions)
+type CommitOption func(opts *commitOptions)
 
-func newCommitOptions(option ...commitOption) commitOptions {
+func newCommitOptions(option ...CommitOption) commitOptions {
 	opts := commitOptions{
 		setHead: false,
 		author: Author{
@@ -23,31 +30,38 @@ func newCommitOptions(option ...commitOption) commitOptions {
 	return opts
 }
 
-func WithAuthor(author Author) commitOption {
+func WithAuthor(author Author) CommitOption {
 	return func(opts *commitOptions) {
 		opts.author = author
 	}
 }
 
-func WithHead() commitOption {
+func WithHead() CommitOption {
 	return func(opts *commitOptions) {
 		opts.setHead = true
 	}
 }
 
-func WithHeaders(headers []Header) commitOption {
+func WithHeaders(headers []Header) CommitOption {
 	return func(opts *commitOptions) {
 		opts.headers = headers
 	}
 }
 
+func WithTime(when time.Time) CommitOption {
+	return func(opts *commitOptions) {
+		opts.when = &when
+	}
+}
+
 type refsOptions struct {
-	prefix *string
+	prefix   *string
+	symbolic bool
 }
 
-type refsOption func(opts *refsOptions)
+type RefsOption func(opts *refsOptions)
 
-func newRefsOptions(option ...refsOption) refsOptions {
+func newRefsOptions(option ...RefsOption) refsOptions {
 	opts := refsOptions{}
 	for _, fn := range option {
 		fn(&opts)
@@ -55,19 +69,27 @@ func newRefsOptions(option ...refsOption) refsOptions {
 	return opts
 }
 
-func WithPrefix(prefix string) refsOption {
+func WithPrefix(prefix string) RefsOption {
 	return func(opts *refsOptions) {
 		opts.prefix = &prefix
 	}
 }
 
+func WithSymbolic() RefsOption {
+	return func(opts *refsOptions) {
+		opts.symbolic = true
+	}
+}
+
 type commitsOptions struct {
-	headerKey *string
+	headerKey   *string
+	headerValue *string
+	hash        *string
 }
 
-type commitsOption func(opts *commitsOptions)
+type CommitsOption func(opts *commitsOptions)
 
-func newCommitsOptions(option ...commitsOption) commitsOptions {
+func newCommitsOptions(option ...CommitsOption) commitsOptions {
 	opts := commitsOptions{}
 	for _, fn := range option {
 		fn(&opts)
@@ -75,8 +97,65 @@ func newCommitsOptions(option ...commitsOption) commitsOptions {
 	return opts
 }
 
-func WithHeaderKey(key string) commitsOption {
+func WithHeaderKey(key string) CommitsOption {
 	return func(opts *commitsOptions) {
 		opts.headerKey = &key
 	}
 }
+
+func WithHeaderValue(value string) CommitsOption {
+	return func(opts *commitsOptions) {
+		opts.headerValue = &value
+	}
+}
+
+func WithHash(hash string) CommitsOption {
+	return func(opts *commitsOptions) {
+		opts.hash = &hash
+	}
+}
+
+func (o commitsOptions) filtered() bool {
+	return o.headerKey != nil || o.headerValue != nil || o.hash != nil
+}
+
+func (o commitsOptions) matches(commit Commit) bool {
+	if o.hash != nil {
+		return strings.EqualFold(commit.Hash, *o.hash)
+	}
+	return slices.ContainsFunc(commit.Headers, o.match)
+}
+
+func (o commitsOptions) match(header Header) bool {
+	if o.headerKey == nil && o.headerValue == nil {
+		return false
+	}
+	return (o.headerKey == nil || header.Key == *o.headerKey) && (o.headerValue == nil || header.Value == *o.headerValue)
+}
+
+type fetchOptions struct {
+	destination *string
+	depth       int
+}
+
+type FetchOption func(opts *fetchOptions)
+
+func newFetchOptions(option ...FetchOption) fetchOptions {
+	opts := fetchOptions{}
+	for _, fn := range option {
+		fn(&opts)
+	}
+	return opts
+}
+
+func WithDestination(refName string) FetchOption {
+	return func(opts *fetchOptions) {
+		opts.destination = &refName
+	}
+}
+
+func WithDepth(depth int) FetchOption {
+	return func(opts *fetchOptions) {
+		opts.depth = depth
+	}
+}
@@ -1,13 +1,21 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"time"
 )
 
 var (
-	ErrReferenceNotFound = errors.New("reference not found")
-	ErrRemoteNotFound    = errors.New("remote not found")
+	ErrReferenceNotFound      = errors.New("reference not found")
+	ErrRemoteNotFound         = errors.New("remote not found")
+	ErrFileNotFound           = errors.New("file not found")
+	ErrObjectNotFound         = errors.New("object not found")
+	ErrRevisionNotFound       = errors.New("revision not found")
+	ErrInvalidTree            = errors.New("tree is invalid")
+	ErrShallowHistory         = errors.New("history is incomplete after a shallow fetch, fetch again without --depth")
+	ErrReferenceChanged       = errors.New("reference has changed")
+	ErrInvalidSourceDateEpoch = errors.New("SOURCE_DATE_EPOCH is invalid")
 )
 
 type Commit struct {
@@ -16,6 +24,7 @@ type Commit struct {
 	When    time.Time
 	Message string
 	Headers []Header
+	Parents []string
 }
 
 type Header struct {
@@ -28,21 +37,57 @@ type Author struct {
 	Email string
 }
 
+type ConfigOption struct {
+	Subsection string
+	Key        string
+	Value      string
+}
+
 type Ref struct {
-	Path string
-	Name string
-	Hash string
+	Path   string
+	Name   string
+	Hash   string
+	Target string
+}
+
+type Change struct {
+	Remote string
+	Ref    string
+	Old    string
+	New    string
 }
 
+func (c Change) Action() string {
+	switch {
+	case c.Old == "":
+		return "create"
+	case c.New == "":
+		return "delete"
+	default:
+		return "update"
+	}
+}
+
+type ChangeFunc func(change Change)
+
 type Repository interface {
 	Head() (*Ref, error)
-	Refs(opts ...refsOption) ([]Ref, error)
+	Refs(opts ...RefsOption) ([]Ref, error)
+	RemoteRefs(ctx context.Context, remoteName string, opts ...RefsOption) ([]Ref, error)
 	Content(refName string, fileName string) (*[]byte, error)
-	Commit(refName string, fileName string, content []byte, msg string, opts ...commitOption) (*Ref, error)
-	Commits(refName string, opts ...commitsOption) ([]Commit, error)
+	Commit(refName string, fileName string, content []byte, msg string, opts ...CommitOption) (*Ref, error)
+	Commits(refName string, opts ...CommitsOption) ([]Commit, error)
+	Lookup(hash string) (*Commit, error)
+	Resolve(rev string) (string, error)
+	Update(refName string, hash string, oldHash string) error
+	Alias(refName string, target string) error
 	Delete(refName string) error
-	Fetch(refName string, remoteName string, force bool) error
-	Push(refName string, remoteName string, force bool) error
-	Mirror(refName string, remoteName string) error
+	DeleteRemote(ctx context.Context, refName string, remoteName string) error
+	Fetch(ctx context.Context, refName string, remoteName string, force bool, opts ...FetchOption) error
+	Push(ctx context.Context, refName string, remoteName string, force bool) error
+	Mirror(ctx context.Context, refName string, remoteName string) error
 	AddRemote(name string, urls ...string) error
+	Config(section string) ([]ConfigOption, error)
+	ObjectFormat() (ObjectFormat, error)
+	DryRun(report ChangeFunc) error
 }
@@ -0,0 +1,433 @@
+package repositorytest
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anselstetter/git-build-number/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type Factory func(t *testing.T, initialCommit bool) (repo repository.Repository, gitDir string)
+
+var when = time.Unix(1700000000, 0).UTC()
+
+var conformance = []struct {
+	name string
+	run  func(t *testing.T, factory Factory)
+}{
+	{name: "head", run: testHead},
+	{name: "commit and content", run: testCommitAndContent},
+	{name: "commits", run: testCommits},
+	{name: "refs and aliases", run: testRefsAndAliases},
+	{name: "update", run: testUpdate},
+	{name: "resolve", run: testResolve},
+	{name: "config", run: testConfig},
+	{name: "object format", run: testObjectFormat},
+	{name: "push and fetch", run: testPushAndFetch},
+	{name: "mirror", run: testMirror},
+	{name: "without remote", run: testWithoutRemote},
+	{name: "dry run", run: testDryRun},
+	{name: "canceled", run: testCanceled},
+	{name: "objects", run: testObjects},
+}
+
+func Run(t *testing.T, factory Factory) {
+	t.Helper()
+
+	for _, c := range conformance {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			c.run(t, factory)
+		})
+	}
+}
+
+func testHead(t *testing.T, factory Factory) {
+	empty, _ := factory(t, false)
+	ref, err := empty.Head()
+	assert.Nil(t, ref)
+	assert.ErrorIs(t, err, repository.ErrReferenceNotFound)
+
+	repo, _ := factory(t, true)
+	head, err := repo.Head()
+	assert.NoError(t, err)
+	assert.Equal(t, "refs/heads/main", head.Path)
+	assert.Equal(t, "main", head.Name)
+	assert.Len(t, head.Hash, 40)
+
+	ref, err = repo.Commit("refs/custom/main", "test", []byte(""), "commit", repository.WithHead())
+	assert.NoError(t, err)
+	head, err = repo.Head()
+	assert.NoError(t, err)
+	assert.Equal(t, ref, head)
+}
+
+func testCommitAndContent(t *testing.T, factory Factory) {
+	repo, _ := factory(t, true)
+	_, _ = repo.Commit("refs/custom/test", "a", []byte("1"), "commit")
+	_, _ = repo.Commit("refs/custom/test", "b", []byte("2"), "commit")
+	ref, err := repo.Commit("refs/custom/test", "a", []byte("3"), "commit")
+	assert.NoError(t, err)
+	assert.Equal(t, "refs/custom/test", ref.Path)
+	assert.Equal(t, "test", ref.Name)
+
+	a, err := repo.Content("refs/custom/test", "a")
+	assert.NoError(t, err)
+	assert.Equal(t, "3", string(*a))
+
+	b, err := repo.Content("refs/custom/test", "b")
+	assert.NoError(t, err)
+	assert.Equal(t, "2", string(*b))
+
+	_, err = repo.Content("refs/custom/test", "c")
+	assert.ErrorIs(t, err, repository.ErrFileNotFound)
+
+	_, err = repo.Content("refs/custom/missing", "a")
+	assert.ErrorIs(t, err, repository.ErrReferenceNotFound)
+}
+
+func testCommits(t *testing.T, factory Factory) {
+	repo, _ := factory(t, true)
+	author := repository.Author{Name: "First Last", Email: "email@domain.tld"}
+	for i, value := range []string{"a", "b", "c"} {
+		_, err := repo.Commit("refs/custom/test", "file", []byte(value), "commit "+value,
+			repository.WithAuthor(author),
+			repository.WithTime(when.Add(time.Duration(i)*time.Second)),
+			repository.WithHeaders([]repository.Header{{Key: "key", Value: value}}),
+		)
+		assert.NoError(t, err)
+	}
+
+	commits, err := repo.Commits("refs/custom/test")
+	assert.NoError(t, err)
+	assert.Len(t, commits, 3)
+	assert.Equal(t, "commit c", commits[0].Message)
+	assert.Equal(t, author, commits[0].Author)
+	assert.True(t, when.Add(2*time.Second).Equal(commits[0].When))
+	assert.Equal(t, []repository.Header{{Key: "key", Value: "c"}}, commits[0].Headers)
+
+	commits, err = repo.Commits("refs/custom/test", repository.WithHeaderValue("b"))
+	assert.NoError(t, err)
+	assert.Len(t, commits, 1)
+	assert.Equal(t, "commit b", commits[0].Message)
+
+	commits, err = repo.Commits("refs/custom/test", repository.WithHeaderKey("missing"))
+	assert.NoError(t, err)
+	assert.Empty(t, commits)
+
+	commits, _ = repo.Commits("refs/custom/test")
+	found, err := repo.Commits("refs/custom/test", repository.WithHash(strings.ToUpper(commits[1].Hash)))
+	assert.NoError(t, err)
+	assert.Equal(t, commits[1:2], found)
+
+	commit, err := repo.Lookup(commits[2].Hash)
+	assert.NoError(t, err)
+	assert.Equal(t, "commit a", commit.Message)
+
+	_, err = repo.Lookup(strings.Repeat("1", 40))
+	assert.ErrorIs(t, err, repository.ErrObjectNotFound)
+
+	_, err = repo.Commits("refs/custom/missing")
+	assert.ErrorIs(t, err, repository.ErrReferenceNotFound)
+}
+
+func testRefsAndAliases(t *testing.T, factory Factory) {
+	repo, _ := factory(t, true)
+	target, _ := repo.Commit("refs/custom/target", "file", []byte("1"), "commit")
+
+	err := repo.Alias("refs/custom/alias", "refs/custom/target")
+	assert.NoError(t, err)
+
+	refs, err := repo.Refs(repository.WithPrefix("refs/custom/"))
+	assert.NoError(t, err)
+	assert.Equal(t, []repository.Ref{*target}, refs)
+
+	refs, err = repo.Refs(repository.WithPrefix("refs/custom/"), repository.WithSymbolic())
+	assert.NoError(t, err)
+	assert.Equal(t, []repository.Ref{
+		{Path: "refs/custom/alias", Name: "alias", Hash: target.Hash, Target: "refs/custom/target"},
+		*target,
+	}, refs)
+
+	commit, err := repo.Commit("refs/custom/alias", "file", []byte("2"), "commit")
+	assert.NoError(t, err)
+	assert.Equal(t, "refs/custom/target", commit.Path)
+
+	content, err := repo.Content("refs/custom/alias", "file")
+	assert.NoError(t, err)
+	assert.Equal(t, "2", string(*content))
+
+	err = repo.Delete("refs/custom/alias")
+	assert.NoError(t, err)
+
+	refs, _ = repo.Refs(repository.WithPrefix("refs/custom/"), repository.WithSymbolic())
+	assert.Equal(t, []repository.Ref{*commit}, refs)
+
+	err = repo.Delete("refs/custom/alias")
+	assert.ErrorIs(t, err, repository.ErrReferenceNotFound)
+}
+
+func testUpdate(t *testing.T, factory Factory) {
+	repo, _ := factory(t, true)
+	head, _ := repo.Head()
+
+	err := repo.Update("refs/custom/new", head.Hash, "")
+	assert.NoError(t, err)
+
+	ref, err := repo.Commit("refs/custom/test", "test", []byte("test"), "commit")
+	assert.NoError(t, err)
+
+	err = repo.Update("refs/custom/test", head.Hash, head.Hash)
+	assert.ErrorIs(t, err, repository.ErrReferenceChanged)
+
+	err = repo.Update("refs/custom/test", head.Hash, ref.Hash)
+	assert.NoError(t, err)
+
+	refs, _ := repo.Refs(repository.WithPrefix("refs/custom/"))
+	assert.Equal(t, []repository.Ref{
+		{Path: "refs/custom/new", Name: "new", Hash: head.Hash},
+		{Path: "refs/custom/test", Name: "test", Hash: head.Hash},
+	}, refs)
+
+	err = repo.Update("refs/custom/test", strings.Repeat("1", 40), "")
+	assert.Error(t, err)
+}
+
+func testResolve(t *testing.T, factory Factory) {
+	repo, _ := factory(t, true)
+	head, _ := repo.Head()
+
+	for _, rev := range []string{"HEAD", "main", head.Hash[:7]} {
+		hash, err := repo.Resolve(rev)
+		assert.NoError(t, err)
+		assert.Equal(t, head.Hash, hash)
+	}
+	_, err := repo.Resolve(strings.Repeat("A", 40))
+	assert.ErrorIs(t, err, repository.ErrRevisionNotFound)
+
+	if unknown, ok := repo.(interface{ SetUnknownRevisions(allow bool) }); ok {
+		unknown.SetUnknownRevisions(true)
+		hash, err := repo.Resolve(strings.Repeat("A", 40))
+		assert.NoError(t, err)
+		assert.Equal(t, strings.Repeat("a", 40), hash)
+	}
+
+	_, err = repo.Resolve("missing")
+	assert.ErrorIs(t, err, repository.ErrRevisionNotFound)
+}
+
+func testConfig(t *testing.T, factory Factory) {
+	repo, path := factory(t, true)
+
+	options, err := repo.Config("custom")
+	assert.NoError(t, err)
+	assert.Empty(t, options)
+
+	if path == "" {
+		return
+	}
+	file, _ := os.OpenFile(filepath.Join(path, "config"), os.O_APPEND|os.O_WRONLY, 0o644)
+	_, _ = file.WriteString("[custom]\n\tkey = value\n[custom \"sub/*\"]\n\tother = 1\n[custom]\n\tlast = 2\n")
+	_ = file.Close()
+
+	options, err = repo.Config("custom")
+	assert.NoError(t, err)
+	assert.Equal(t, []repository.ConfigOption{
+		{Key: "key", Value: "value"},
+		{Key: "last", Value: "2"},
+		{Subsection: "sub/*", Key: "other", Value: "1"},
+	}, options)
+}
+
+func testObjectFormat(t *testing.T, factory Factory) {
+	repo, _ := factory(t, false)
+	format, err := repo.ObjectFormat()
+	assert.NoError(t, err)
+	assert.Equal(t, repository.SHA1, format)
+}
+
+func testPushAndFetch(t *testing.T, factory Factory) {
+	repo, _ := factory(t, true)
+	remote, path := NewRemote(t, false)
+	_ = repo.AddRemote("origin", path)
+	ref, _ := repo.Commit("refs/custom/test", "test", []byte("1"), "commit")
+
+	err := repo.Push(t.Context(), "refs/custom/test", "origin", false)
+	assert.NoError(t, err)
+
+	err = repo.Push(t.Context(), "refs/custom/test", "origin", false)
+	assert.NoError(t, err)
+
+	refs, err := repo.RemoteRefs(t.Context(), "origin", repository.WithPrefix("refs/custom/"))
+	assert.NoError(t, err)
+	assert.Equal(t, []repository.Ref{*ref}, refs)
+
+	other, _ := factory(t, false)
+	_ = other.AddRemote("origin", path)
+
+	err = other.Fetch(t.Context(), "refs/custom/*", "origin", true, repository.WithDestination("refs/remotes/origin/custom/*"))
+	assert.NoError(t, err)
+
+	refs, _ = other.Refs()
+	assert.Equal(t, []repository.Ref{{Path: "refs/remotes/origin/custom/test", Name: "test", Hash: ref.Hash}}, refs)
+
+	err = other.Fetch(t.Context(), "refs/custom/missing", "origin", false)
+	assert.ErrorIs(t, err, repository.ErrReferenceNotFound)
+
+	_, _ = repo.Commit("refs/custom/test", "test", []byte("2"), "commit")
+	_ = repo.Push(t.Context(), "refs/custom/test", "origin", false)
+	_ = other.Fetch(t.Context(), "refs/custom/test", "origin", false)
+	_, _ = other.Commit("refs/custom/test", "test", []byte("3"), "diverged", repository.WithTime(when))
+	_, _ = repo.Commit("refs/custom/test", "test", []byte("4"), "commit")
+	_ = repo.Push(t.Context(), "refs/custom/test", "origin", false)
+
+	err = other.Push(t.Context(), "refs/custom/test", "origin", false)
+	assert.ErrorIs(t, err, repository.ErrReferenceChanged)
+
+	err = other.Push(t.Context(), "refs/custom/test", "origin", true)
+	assert.NoError(t, err)
+
+	err = repo.DeleteRemote(t.Context(), "refs/custom/test", "origin")
+	assert.NoError(t, err)
+
+	refs, _ = remote.Refs(repository.WithPrefix("refs/custom/"))
+	assert.Empty(t, refs)
+
+	err = repo.DeleteRemote(t.Context(), "refs/custom/test", "origin")
+	assert.ErrorIs(t, err, repository.ErrReferenceNotFound)
+}
+
+func testMirror(t *testing.T, factory Factory) {
+	repo, _ := factory(t, true)
+	remote, path := NewRemote(t, true)
+	_ = repo.AddRemote("origin", path)
+	_, _ = remote.Commit("refs/custom/stale", "test", []byte("1"), "commit")
+	ref, _ := repo.Commit("refs/custom/test", "test", []byte("1"), "commit")
+
+	err := repo.Mirror(t.Context(), "refs/custom/", "origin")
+	assert.NoError(t, err)
+
+	refs, _ := remote.Refs(repository.WithPrefix("refs/custom/"))
+	assert.Equal(t, []repository.Ref{*ref}, refs)
+}
+
+func testWithoutRemote(t *testing.T, factory Factory) {
+	repo, _ := factory(t, true)
+
+	_, err := repo.RemoteRefs(t.Context(), "origin")
+	assert.ErrorIs(t, err, repository.ErrRemoteNotFound)
+	assert.ErrorIs(t, repo.Push(t.Context(), "refs/heads/main", "origin", false), repository.ErrRemoteNotFound)
+	assert.ErrorIs(t, repo.Fetch(t.Context(), "refs/heads/main", "origin", false), repository.ErrRemoteNotFound)
+	assert.ErrorIs(t, repo.Mirror(t.Context(), "refs/heads/", "origin"), repository.ErrRemoteNotFound)
+	assert.ErrorIs(t, repo.DeleteRemote(t.Context(), "refs/heads/main", "origin"), repository.ErrRemoteNotFound)
+}
+
+func testDryRun(t *testing.T, factory Factory) {
+	repo, gitDir := factory(t, true)
+	remote, path := NewRemote(t, false)
+	_ = repo.AddRemote("origin", path)
+	ref, _ := repo.Commit("refs/custom/keep", "test", []byte("test"), "commit")
+	_, _ = remote.Commit("refs/custom/remote", "test", []byte("remote"), "commit")
+	objects := countObjects(t, gitDir)
+
+	changes := []repository.Change{}
+	err := repo.DryRun(func(change repository.Change) {
+		changes = append(changes, change)
+	})
+	assert.NoError(t, err)
+
+	updated, err := repo.Commit("refs/custom/keep", "test", []byte("updated"), "commit")
+	assert.NoError(t, err)
+
+	content, err := repo.Content("refs/custom/keep", "test")
+	assert.NoError(t, err)
+	assert.Equal(t, "updated", string(*content))
+
+	created, err := repo.Commit("refs/custom/new", "test", []byte("new"), "commit")
+	assert.NoError(t, err)
+
+	err = repo.Delete("refs/custom/new")
+	assert.NoError(t, err)
+
+	err = repo.Push(t.Context(), "refs/custom/keep", "origin", false)
+	assert.NoError(t, err)
+
+	err = repo.Fetch(t.Context(), "refs/custom/remote", "origin", false, repository.WithDestination("refs/remotes/origin/custom/remote"))
+	assert.NoError(t, err)
+	content, err = repo.Content("refs/remotes/origin/custom/remote", "test")
+	assert.NoError(t, err)
+	assert.Equal(t, "remote", string(*content))
+
+	refs, err := repo.Refs(repository.WithPrefix("refs/custom"))
+	assert.NoError(t, err)
+	assert.Equal(t, []repository.Ref{*updated}, refs)
+
+	assert.Equal(t, []repository.Change{
+		{Ref: "refs/custom/keep", Old: ref.Hash, New: updated.Hash},
+		{Ref: "refs/custom/new", New: created.Hash},
+		{Ref: "refs/custom/new", Old: created.Hash},
+		{Remote: "origin", Ref: "refs/custom/keep", New: updated.Hash},
+	}, changes)
+
+	remoteRefs, _ := remote.Refs(repository.WithPrefix("refs/custom/keep"))
+	assert.Empty(t, remoteRefs)
+	assert.Equal(t, objects, countObjects(t, gitDir))
+}
+
+func countObjects(t *testing.T, gitDir string) int {
+	t.Helper()
+
+	if gitDir == "" {
+		return 0
+	}
+	count := 0
+	err := filepath.WalkDir(filepath.Join(gitDir, "objects"), func(path string, entry fs.DirEntry, err error) error {
+		if err == nil && !entry.IsDir() {
+			count++
+		}
+		return err
+	})
+	assert.NoError(t, err)
+	return count
+}
+
+func testCanceled(t *testing.T, factory Factory) {
+	repo, _ := factory(t, true)
+	remote, path := NewRemote(t, true)
+	_ = repo.AddRemote("origin", path)
+	_, _ = repo.Commit("refs/custom/test", "test", []byte("1"), "commit")
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	_, err := repo.RemoteRefs(ctx, "origin")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, repo.Push(ctx, "refs/custom/test", "origin", false), context.Canceled)
+	assert.ErrorIs(t, repo.Mirror(ctx, "refs/custom/", "origin"), context.Canceled)
+	assert.ErrorIs(t, repo.Fetch(ctx, "refs/heads/main", "origin", false), context.Canceled)
+	assert.ErrorIs(t, repo.DeleteRemote(ctx, "refs/heads/main", "origin"), context.Canceled)
+
+	refs, _ := remote.Refs(repository.WithPrefix("refs/custom/"))
+	assert.Empty(t, refs)
+	head, err := remote.Head()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, head.Hash)
+}
+
+func testObjects(t *testing.T, factory Factory) {
+	repo, _ := factory(t, false)
+	_, _ = repo.Commit("refs/custom/test", "a", []byte("1"), "first", repository.WithTime(when))
+
+	ref, err := repo.Commit("refs/custom/test", "b", []byte("2"), "second",
+		repository.WithTime(when),
+		repository.WithHeaders([]repository.Header{{Key: "2", Value: strings.Repeat("f", 40)}}),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "dc7e66070b7d729c6ce10056ac35902bc9401efd", ref.Hash)
+}
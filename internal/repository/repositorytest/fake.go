@@ -0,0 +1,238 @@
+package repositorytest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/anselstetter/git-build-number/internal/repository"
+)
+
+type Fault struct {
+	Method  string
+	Err     error
+	Times   int
+	Before  func()
+	Content []byte
+}
+
+type fault struct {
+	Fault
+	fired int
+}
+
+type Fake struct {
+	repository.Repository
+	mu     sync.Mutex
+	faults []*fault
+	calls  map[string]int
+}
+
+func NewFake(repo repository.Repository) *Fake {
+	return &Fake{
+		Repository: repo,
+		calls:      map[string]int{},
+	}
+}
+
+func (f *Fake) Inject(faults ...Fault) *Fake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, injected := range faults {
+		f.faults = append(f.faults, &fault{Fault: injected})
+	}
+	return f
+}
+
+func (f *Fake) Fail(method string, err error) *Fake {
+	return f.Inject(Fault{Method: method, Err: err})
+}
+
+func (f *Fake) FailOnce(method string, err error) *Fake {
+	return f.Inject(Fault{Method: method, Err: err, Times: 1})
+}
+
+func (f *Fake) Before(method string, hook func()) *Fake {
+	return f.Inject(Fault{Method: method, Before: hook, Times: 1})
+}
+
+func (f *Fake) Corrupt(content []byte) *Fake {
+	return f.Inject(Fault{Method: "Content", Content: content})
+}
+
+func (f *Fake) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.faults = nil
+	f.calls = map[string]int{}
+}
+
+func (f *Fake) Calls(method string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.calls[method]
+}
+
+func (f *Fake) intercept(method string) (*Fault, error) {
+	f.mu.Lock()
+	f.calls[method]++
+
+	var found *fault
+	for _, fault := range f.faults {
+		if fault.Method != method || (fault.Times > 0 && fault.fired >= fault.Times) {
+			continue
+		}
+		fault.fired++
+		found = fault
+		break
+	}
+	f.mu.Unlock()
+
+	if found == nil {
+		return nil, nil
+	}
+	if found.Before != nil {
+		found.Before()
+	}
+	return &found.Fault, found.Err
+}
+
+func (f *Fake) Head() (*repository.Ref, error) {
+	if _, err := f.intercept("Head"); err != nil {
+		return nil, err
+	}
+	return f.Repository.Head()
+}
+
+func (f *Fake) Refs(opts ...repository.RefsOption) ([]repository.Ref, error) {
+	if _, err := f.intercept("Refs"); err != nil {
+		return nil, err
+	}
+	return f.Repository.Refs(opts...)
+}
+
+func (f *Fake) RemoteRefs(ctx context.Context, remoteName string, opts ...repository.RefsOption) ([]repository.Ref, error) {
+	if _, err := f.intercept("RemoteRefs"); err != nil {
+		return nil, err
+	}
+	return f.Repository.RemoteRefs(ctx, remoteName, opts...)
+}
+
+func (f *Fake) Content(refName string, fileName string) (*[]byte, error) {
+	fault, err := f.intercept("Content")
+	if err != nil {
+		return nil, err
+	}
+	if fault != nil && fault.Content != nil {
+		content := append([]byte{}, fault.Content...)
+		return &content, nil
+	}
+	return f.Repository.Content(refName, fileName)
+}
+
+func (f *Fake) Commit(refName string, fileName string, content []byte, msg string, opts ...repository.CommitOption) (*repository.Ref, error) {
+	if _, err := f.intercept("Commit"); err != nil {
+		return nil, err
+	}
+	return f.Repository.Commit(refName, fileName, content, msg, opts...)
+}
+
+func (f *Fake) Commits(refName string, opts ...repository.CommitsOption) ([]repository.Commit, error) {
+	if _, err := f.intercept("Commits"); err != nil {
+		return nil, err
+	}
+	return f.Repository.Commits(refName, opts...)
+}
+
+func (f *Fake) Lookup(hash string) (*repository.Commit, error) {
+	if _, err := f.intercept("Lookup"); err != nil {
+		return nil, err
+	}
+	return f.Repository.Lookup(hash)
+}
+
+func (f *Fake) Resolve(rev string) (string, error) {
+	if _, err := f.intercept("Resolve"); err != nil {
+		return "", err
+	}
+	return f.Repository.Resolve(rev)
+}
+
+func (f *Fake) Update(refName string, hash string, oldHash string) error {
+	if _, err := f.intercept("Update"); err != nil {
+		return err
+	}
+	return f.Repository.Update(refName, hash, oldHash)
+}
+
+func (f *Fake) Alias(refName string, target string) error {
+	if _, err := f.intercept("Alias"); err != nil {
+		return err
+	}
+	return f.Repository.Alias(refName, target)
+}
+
+func (f *Fake) Delete(refName string) error {
+	if _, err := f.intercept("Delete"); err != nil {
+		return err
+	}
+	return f.Repository.Delete(refName)
+}
+
+func (f *Fake) DeleteRemote(ctx context.Context, refName string, remoteName string) error {
+	if _, err := f.intercept("DeleteRemote"); err != nil {
+		return err
+	}
+	return f.Repository.DeleteRemote(ctx, refName, remoteName)
+}
+
+func (f *Fake) Fetch(ctx context.Context, refName string, remoteName string, force bool, opts ...repository.FetchOption) error {
+	if _, err := f.intercept("Fetch"); err != nil {
+		return err
+	}
+	return f.Repository.Fetch(ctx, refName, remoteName, force, opts...)
+}
+
+func (f *Fake) Push(ctx context.Context, refName string, remoteName string, force bool) error {
+	if _, err := f.intercept("Push"); err != nil {
+		return err
+	}
+	return f.Repository.Push(ctx, refName, remoteName, force)
+}
+
+func (f *Fake) Mirror(ctx context.Context, refName string, remoteName string) error {
+	if _, err := f.intercept("Mirror"); err != nil {
+		return err
+	}
+	return f.Repository.Mirror(ctx, refName, remoteName)
+}
+
+func (f *Fake) AddRemote(name string, urls ...string) error {
+	if _, err := f.intercept("AddRemote"); err != nil {
+		return err
+	}
+	return f.Repository.AddRemote(name, urls...)
+}
+
+func (f *Fake) Config(section string) ([]repository.ConfigOption, error) {
+	if _, err := f.intercept("Config"); err != nil {
+		return nil, err
+	}
+	return f.Repository.Config(section)
+}
+
+func (f *Fake) ObjectFormat() (repository.ObjectFormat, error) {
+	if _, err := f.intercept("ObjectFormat"); err != nil {
+		return "", err
+	}
+	return f.Repository.ObjectFormat()
+}
+
+func (f *Fake) DryRun(report repository.ChangeFunc) error {
+	if _, err := f.intercept("DryRun"); err != nil {
+		return err
+	}
+	return f.Repository.DryRun(report)
+}
@@ -0,0 +1,115 @@
+package repositorytest_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/anselstetter/git-build-number/internal/repository"
+	"github.com/anselstetter/git-build-number/internal/repository/repositorytest"
+	"github.com/stretchr/testify/assert"
+)
+
+var errFault = errors.New("fault")
+
+func newFake(t *testing.T) *repositorytest.Fake {
+	t.Helper()
+
+	repo, _, err := repository.NewGitInMemoryRepository(true)
+	assert.NoError(t, err)
+	return repositorytest.NewFake(repo)
+}
+
+func TestFake(t *testing.T) {
+	t.Run("delegates", func(t *testing.T) {
+		t.Parallel()
+
+		fake := newFake(t)
+
+		ref, err := fake.Commit("refs/custom/test", "test", []byte("1"), "commit")
+		assert.NoError(t, err)
+
+		content, err := fake.Content("refs/custom/test", "test")
+		assert.NoError(t, err)
+		assert.Equal(t, "1", string(*content))
+		assert.Equal(t, 1, fake.Calls("Commit"))
+		assert.Equal(t, 1, fake.Calls("Content"))
+		assert.Equal(t, 0, fake.Calls("Push"))
+
+		hash, err := fake.Resolve("refs/custom/test")
+		assert.NoError(t, err)
+		assert.Equal(t, ref.Hash, hash)
+	})
+	t.Run("fail", func(t *testing.T) {
+		t.Parallel()
+
+		fake := newFake(t).Fail("Head", errFault)
+
+		for range 2 {
+			ref, err := fake.Head()
+			assert.Nil(t, ref)
+			assert.ErrorIs(t, err, errFault)
+		}
+		assert.Equal(t, 2, fake.Calls("Head"))
+	})
+	t.Run("fail once", func(t *testing.T) {
+		t.Parallel()
+
+		fake := newFake(t).FailOnce("Commit", errFault)
+
+		ref, err := fake.Commit("refs/custom/test", "test", []byte("1"), "commit")
+		assert.Nil(t, ref)
+		assert.ErrorIs(t, err, errFault)
+
+		_, err = fake.Commit("refs/custom/test", "test", []byte("1"), "commit")
+		assert.NoError(t, err)
+	})
+	t.Run("times", func(t *testing.T) {
+		t.Parallel()
+
+		fake := newFake(t).Inject(repositorytest.Fault{Method: "Refs", Err: errFault, Times: 2})
+
+		_, err := fake.Refs()
+		assert.ErrorIs(t, err, errFault)
+		_, err = fake.Refs()
+		assert.ErrorIs(t, err, errFault)
+		_, err = fake.Refs()
+		assert.NoError(t, err)
+	})
+	t.Run("before", func(t *testing.T) {
+		t.Parallel()
+
+		fake := newFake(t)
+		hooks := 0
+		fake.Before("Delete", func() {
+			hooks++
+		})
+
+		_ = fake.Delete("refs/custom/missing")
+		_ = fake.Delete("refs/custom/missing")
+		assert.Equal(t, 1, hooks)
+		assert.Equal(t, 2, fake.Calls("Delete"))
+	})
+	t.Run("corrupt", func(t *testing.T) {
+		t.Parallel()
+
+		fake := newFake(t).Corrupt([]byte("garbage"))
+		_, _ = fake.Commit("refs/custom/test", "test", []byte("1"), "commit")
+
+		content, err := fake.Content("refs/custom/test", "test")
+		assert.NoError(t, err)
+		assert.Equal(t, "garbage", string(*content))
+	})
+	t.Run("reset", func(t *testing.T) {
+		t.Parallel()
+
+		fake := newFake(t).Fail("ObjectFormat", errFault)
+		_, _ = fake.ObjectFormat()
+
+		fake.Reset()
+
+		format, err := fake.ObjectFormat()
+		assert.NoError(t, err)
+		assert.Equal(t, repository.SHA1, format)
+		assert.Equal(t, 1, fake.Calls("ObjectFormat"))
+	})
+}
@@ -0,0 +1,53 @@
+package repositorytest
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/anselstetter/git-build-number/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+const RemoteName = "origin"
+
+func NewRemote(t *testing.T, initialCommit bool) (repository.Repository, string) {
+	t.Helper()
+
+	remote, path, err := repository.NewGitTempBareRepository(initialCommit)
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		_ = os.RemoveAll(*path)
+	})
+	return remote, *path
+}
+
+func Clone(t *testing.T, url string) repository.Repository {
+	t.Helper()
+
+	repo, _, err := repository.NewGitInMemoryRepository(false)
+	assert.NoError(t, err)
+	repo.SetUnknownRevisions(true)
+	assert.NoError(t, repo.AddRemote(RemoteName, url))
+	return repo
+}
+
+func AdvanceRemote(t *testing.T, url string, refName string, update func(other repository.Repository)) {
+	t.Helper()
+
+	other := Clone(t, url)
+	err := other.Fetch(t.Context(), refName, RemoteName, true)
+	if err != nil && !errors.Is(err, repository.ErrReferenceNotFound) {
+		assert.NoError(t, err)
+	}
+	update(other)
+	assert.NoError(t, other.Push(t.Context(), refName, RemoteName, false))
+}
+
+func ConcurrentUpdate(t *testing.T, url string, refName string, update func(other repository.Repository)) func() {
+	t.Helper()
+
+	return func() {
+		AdvanceRemote(t, url, refName, update)
+	}
+}
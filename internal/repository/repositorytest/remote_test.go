@@ -0,0 +1,44 @@
+package repositorytest_test
+
+import (
+	"testing"
+
+	"github.com/anselstetter/git-build-number/internal/repository"
+	"github.com/anselstetter/git-build-number/internal/repository/repositorytest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdvanceRemote(t *testing.T) {
+	t.Run("creates the ref", func(t *testing.T) {
+		t.Parallel()
+
+		remote, path := repositorytest.NewRemote(t, false)
+
+		repositorytest.AdvanceRemote(t, path, "refs/custom/test", func(other repository.Repository) {
+			_, err := other.Commit("refs/custom/test", "test", []byte("1"), "commit")
+			assert.NoError(t, err)
+		})
+
+		content, err := remote.Content("refs/custom/test", "test")
+		assert.NoError(t, err)
+		assert.Equal(t, "1", string(*content))
+	})
+	t.Run("concurrent update", func(t *testing.T) {
+		t.Parallel()
+
+		_, path := repositorytest.NewRemote(t, false)
+		repo := repositorytest.Clone(t, path)
+		_, _ = repo.Commit("refs/custom/test", "test", []byte("1"), "commit")
+		_ = repo.Push(t.Context(), "refs/custom/test", repositorytest.RemoteName, false)
+
+		fake := repositorytest.NewFake(repo)
+		fake.Before("Push", repositorytest.ConcurrentUpdate(t, path, "refs/custom/test", func(other repository.Repository) {
+			_, err := other.Commit("refs/custom/test", "test", []byte("2"), "commit")
+			assert.NoError(t, err)
+		}))
+		_, _ = fake.Commit("refs/custom/test", "test", []byte("3"), "commit")
+
+		err := fake.Push(t.Context(), "refs/custom/test", repositorytest.RemoteName, false)
+		assert.ErrorIs(t, err, repository.ErrReferenceChanged)
+	})
+}
@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/go-git/go-git/v6/plumbing/protocol"
+	"github.com/go-git/go-git/v6/plumbing/transport"
+	githttp "github.com/go-git/go-git/v6/plumbing/transport/http"
+	"github.com/go-git/go-git/v6/storage"
+	"golang.org/x/net/http/httpproxy"
+)
+
+var (
+	ErrInvalidCABundle   = errors.New("CA bundle is invalid")
+	ErrInvalidClientCert = errors.New("client certificate is invalid")
+	ErrInvalidProxy      = errors.New("proxy URL is invalid")
+	ErrMissingClientCert = errors.New("client key requires a client certificate")
+)
+
+type TLS struct {
+	CABundle   string
+	Insecure   bool
+	ClientCert string
+	ClientKey  string
+	Proxy      string
+	NoProxy    string
+}
+
+type transportOptions struct {
+	caBundle []byte
+	insecure bool
+	proxy    string
+	noProxy  string
+	client   transport.Transport
+}
+
+type clientKey struct{}
+
+var registerClients sync.Once
+
+func TLSFromConfig(repo Repository) (TLS, error) {
+	options, err := repo.Config("http")
+	if err != nil {
+		return TLS{}, err
+	}
+	config := TLS{}
+	for _, option := range options {
+		if option.Subsection != "" {
+			continue
+		}
+		switch strings.ToLower(option.Key) {
+		case "sslcainfo":
+			config.CABundle = option.Value
+		case "sslverify":
+			config.Insecure = slices.Contains([]string{"false", "no", "off", "0"}, strings.ToLower(option.Value))
+		case "sslcert":
+			config.ClientCert = option.Value
+		case "sslkey":
+			config.ClientKey = option.Value
+		case "proxy":
+			config.Proxy = option.Value
+		}
+	}
+	return config, nil
+}
+
+func (t TLS) Merge(override TLS) TLS {
+	if override.CABundle != "" {
+		t.CABundle = override.CABundle
+	}
+	if override.Insecure {
+		t.Insecure = true
+	}
+	if override.ClientCert != "" {
+		t.ClientCert = override.ClientCert
+		t.ClientKey = override.ClientKey
+	}
+	if override.Proxy != "" {
+		t.Proxy = override.Proxy
+	}
+	if override.NoProxy != "" {
+		t.NoProxy = override.NoProxy
+	}
+	return t
+}
+
+func (g *GitRepository) SetTLS(config TLS) error {
+	options := &transportOptions{
+		insecure: config.Insecure,
+		proxy:    config.Proxy,
+		noProxy:  config.NoProxy,
+	}
+	if config.CABundle != "" {
+		bundle, err := os.ReadFile(config.CABundle)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidCABundle, err)
+		}
+		if !x509.NewCertPool().AppendCertsFromPEM(bundle) {
+			return fmt.Errorf("%w: %s contains no certificates", ErrInvalidCABundle, config.CABundle)
+		}
+		options.caBundle = bundle
+	}
+	if config.Proxy != "" {
+		if _, err := url.Parse(config.Proxy); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidProxy, redactString(config.Proxy))
+		}
+	}
+	if config.ClientKey != "" && config.ClientCert == "" {
+		return fmt.Errorf("%w: %s", ErrMissingClientCert, config.ClientKey)
+	}
+	if config.ClientCert != "" {
+		client, err := clientCertTransport(config.ClientCert, config.ClientKey)
+		if err != nil {
+			return err
+		}
+		options.client = client
+	}
+	g.transport = options
+	return nil
+}
+
+func (t *transportOptions) proxyOptions(remoteURL string) transport.ProxyOptions {
+	if t == nil || t.proxy == "" {
+		return transport.ProxyOptions{}
+	}
+	target, err := url.Parse(remoteURL)
+	if err != nil {
+		return transport.ProxyOptions{}
+	}
+	proxy := httpproxy.Config{HTTPProxy: t.proxy, HTTPSProxy: t.proxy, NoProxy: t.noProxy}
+	proxyURL, err := proxy.ProxyFunc()(target)
+	if err != nil || proxyURL == nil {
+		return transport.ProxyOptions{}
+	}
+	return transport.ProxyOptions{URL: proxyURL.String()}
+}
+
+func (t *transportOptions) context(ctx context.Context) context.Context {
+	if t == nil || t.client == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, clientKey{}, t.client)
+}
+
+func clientCertTransport(certFile string, keyFile string) (transport.Transport, error) {
+	if keyFile == "" {
+		keyFile = certFile
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidClientCert, err)
+	}
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	if base.TLSClientConfig == nil {
+		base.TLSClientConfig = &tls.Config{}
+	}
+	base.TLSClientConfig.Certificates = []tls.Certificate{cert}
+
+	// go-git looks transports up in a global registry, so the https entry
+	// picks the transport of the repository from the context of each call.
+	registerClients.Do(func() {
+		transport.Register("https", &contextTransport{fallback: githttp.DefaultTransport})
+	})
+	return githttp.NewTransport(&githttp.TransportOptions{Client: &http.Client{Transport: base}}), nil
+}
+
+type contextTransport struct {
+	fallback transport.Transport
+}
+
+func (t *contextTransport) NewSession(st storage.Storer, ep *transport.Endpoint, auth transport.AuthMethod) (transport.Session, error) {
+	return &contextSession{fallback: t.fallback, st: st, ep: ep, auth: auth}, nil
+}
+
+func (t *contextTransport) SupportedProtocols() []protocol.Version {
+	return t.fallback.SupportedProtocols()
+}
+
+type contextSession struct {
+	fallback transport.Transport
+	st       storage.Storer
+	ep       *transport.Endpoint
+	auth     transport.AuthMethod
+}
+
+func (s *contextSession) Handshake(ctx context.Context, service transport.Service, params ...string) (transport.Connection, error) {
+	client := s.fallback
+	if owned, ok := ctx.Value(clientKey{}).(transport.Transport); ok {
+		client = owned
+	}
+	session, err := client.NewSession(s.st, s.ep, s.auth)
+	if err != nil {
+		return nil, err
+	}
+	return session.Handshake(ctx, service, params...)
+}
@@ -0,0 +1,242 @@
+package stamp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+)
+
+var (
+	ErrUnknownFormat  = errors.New("unknown format")
+	ErrMissingPattern = errors.New("please provide a pattern")
+	ErrMissingGroup   = errors.New("pattern needs at least one capture group")
+	ErrNoMatch        = errors.New("pattern did not match")
+	ErrInvalidJSON    = errors.New("invalid JSON")
+)
+
+var jsonStringValue = regexp.MustCompile(`^\s*:\s*"([^"]*)"`)
+
+type Replacer func(content []byte, number int64) ([]byte, error)
+
+type Format string
+
+const (
+	FormatGo     Format = "go"
+	FormatNpm    Format = "npm"
+	FormatGradle Format = "gradle"
+	FormatPlist  Format = "plist"
+	FormatDotnet Format = "dotnet"
+	FormatRegex  Format = "regex"
+)
+
+var Formats = []Format{FormatGo, FormatNpm, FormatGradle, FormatPlist, FormatDotnet, FormatRegex}
+
+func (f Format) DefaultKey() string {
+	switch f {
+	case FormatGo:
+		return "main.Version"
+	case FormatNpm:
+		return "version"
+	case FormatGradle:
+		return "versionCode"
+	case FormatPlist:
+		return "CFBundleVersion"
+	case FormatDotnet:
+		return "Version"
+	case FormatRegex:
+		return ""
+	default:
+		return ""
+	}
+}
+
+func (f Format) DefaultFile() string {
+	switch f {
+	case FormatNpm:
+		return "package.json"
+	case FormatGradle:
+		return "gradle.properties"
+	case FormatPlist:
+		return "Info.plist"
+	case FormatDotnet:
+		return "Directory.Build.props"
+	case FormatGo, FormatRegex:
+		return ""
+	default:
+		return ""
+	}
+}
+
+func (f Format) Pattern(key string) (*regexp.Regexp, error) {
+	quoted := regexp.QuoteMeta(key)
+
+	switch f {
+	case FormatNpm:
+		return regexp.MustCompile(fmt.Sprintf(`"%s"\s*:\s*"([^"]*)"`, quoted)), nil
+	case FormatGradle:
+		return regexp.MustCompile(fmt.Sprintf(`(?m)^[ \t]*%s[ \t]*=[ \t]*(.*?)[ \t]*$`, quoted)), nil
+	case FormatPlist:
+		return regexp.MustCompile(fmt.Sprintf(`<key>%s</key>\s*<string>([^<]*)</string>`, quoted)), nil
+	case FormatDotnet:
+		return regexp.MustCompile(fmt.Sprintf(`<%s>([^<]*)</%s>`, quoted, quoted)), nil
+	case FormatRegex:
+		if key == "" {
+			return nil, ErrMissingPattern
+		}
+		regex, err := regexp.Compile(key)
+		if err != nil {
+			return nil, err
+		}
+		if regex.NumSubexp() == 0 {
+			return nil, ErrMissingGroup
+		}
+		return regex, nil
+	case FormatGo:
+		return nil, fmt.Errorf("%w: %s can't be written to a file", ErrUnknownFormat, f)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownFormat, f)
+	}
+}
+
+func (f Format) Replacer(key string) (Replacer, error) {
+	if f == FormatNpm {
+		return func(content []byte, number int64) ([]byte, error) {
+			return ReplaceJSON(key, content, number)
+		}, nil
+	}
+	regex, err := f.Pattern(key)
+	if err != nil {
+		return nil, err
+	}
+	return func(content []byte, number int64) ([]byte, error) {
+		return Replace(regex, content, number)
+	}, nil
+}
+
+func ParseFormat(s string) (Format, error) {
+	for _, format := range Formats {
+		if string(format) == s {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrUnknownFormat, s)
+}
+
+func LdFlags(key string, number int64) string {
+	return fmt.Sprintf("-X %s=%d", key, number)
+}
+
+func Replace(regex *regexp.Regexp, content []byte, number int64) ([]byte, error) {
+	matches := regex.FindAllSubmatchIndex(content, -1)
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoMatch, regex.String())
+	}
+	value := strconv.FormatInt(number, 10)
+	out := make([]byte, 0, len(content))
+	last := 0
+
+	for _, match := range matches {
+		start, end := match[2], match[3]
+		if start < 0 {
+			continue
+		}
+		out = append(out, content[last:start]...)
+		out = append(out, value...)
+		last = end
+	}
+	out = append(out, content[last:]...)
+
+	return out, nil
+}
+
+func ReplaceJSON(key string, content []byte, number int64) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	if token, err := decoder.Token(); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidJSON, err)
+	} else if token != json.Delim('{') {
+		return nil, fmt.Errorf("%w: not an object", ErrInvalidJSON)
+	}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidJSON, err)
+		}
+		offset := decoder.InputOffset()
+		if token == key {
+			match := jsonStringValue.FindSubmatchIndex(content[offset:])
+			if match == nil {
+				break
+			}
+			start, end := int(offset)+match[2], int(offset)+match[3]
+			out := make([]byte, 0, len(content))
+			out = append(out, content[:start]...)
+			out = append(out, strconv.FormatInt(number, 10)...)
+			return append(out, content[end:]...), nil
+		}
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidJSON, err)
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", ErrNoMatch, key)
+}
+
+func Files(paths []string, replace Replacer, number int64) error {
+	temps := make([]string, 0, len(paths))
+	cleanup := func() {
+		for _, temp := range temps {
+			_ = os.Remove(temp)
+		}
+	}
+	for _, path := range paths {
+		temp, err := stampTemp(path, replace, number)
+		if err != nil {
+			cleanup()
+			return err
+		}
+		temps = append(temps, temp)
+	}
+	for i, path := range paths {
+		if err := os.Rename(temps[i], path); err != nil {
+			cleanup()
+			return err
+		}
+	}
+	return nil
+}
+
+func stampTemp(path string, replace Replacer, number int64) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	stamped, err := replace(content, number)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", path, err)
+	}
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return "", err
+	}
+	_, err = file.Write(stamped)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(file.Name(), info.Mode())
+	}
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return "", err
+	}
+	return file.Name(), nil
+}
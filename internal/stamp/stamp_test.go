@@ -0,0 +1,180 @@
+package stamp_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/anselstetter/git-build-number/internal/stamp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplace(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   stamp.Format
+		key      string
+		content  string
+		expected string
+		err      error
+	}{
+		{
+			name:     "npm",
+			format:   stamp.FormatNpm,
+			content:  "{\n  \"name\": \"app\",\n  \"version\": \"1.0.0\"\n}\n",
+			expected: "{\n  \"name\": \"app\",\n  \"version\": \"42\"\n}\n",
+		},
+		{
+			name:     "npm with nested version",
+			format:   stamp.FormatNpm,
+			content:  "{\n  \"engines\": {\"version\": \"18\"},\n  \"version\" : \"1.0.0\",\n  \"publishConfig\": {\"version\": \"2\"}\n}\n",
+			expected: "{\n  \"engines\": {\"version\": \"18\"},\n  \"version\" : \"42\",\n  \"publishConfig\": {\"version\": \"2\"}\n}\n",
+		},
+		{
+			name:    "npm without top-level version",
+			format:  stamp.FormatNpm,
+			content: "{\"engines\": {\"version\": \"18\"}}",
+			err:     stamp.ErrNoMatch,
+		},
+		{
+			name:    "npm with invalid json",
+			format:  stamp.FormatNpm,
+			content: "{\"name\": ",
+			err:     stamp.ErrInvalidJSON,
+		},
+		{
+			name:     "gradle",
+			format:   stamp.FormatGradle,
+			content:  "versionName=1.0\nversionCode = 7\n",
+			expected: "versionName=1.0\nversionCode = 42\n",
+		},
+		{
+			name:     "plist",
+			format:   stamp.FormatPlist,
+			content:  "<dict>\n\t<key>CFBundleVersion</key>\n\t<string>1</string>\n</dict>\n",
+			expected: "<dict>\n\t<key>CFBundleVersion</key>\n\t<string>42</string>\n</dict>\n",
+		},
+		{
+			name:     "dotnet",
+			format:   stamp.FormatDotnet,
+			content:  "<Project><PropertyGroup><Version>1.0.0</Version></PropertyGroup></Project>",
+			expected: "<Project><PropertyGroup><Version>42</Version></PropertyGroup></Project>",
+		},
+		{
+			name:     "dotnet with custom key",
+			format:   stamp.FormatDotnet,
+			key:      "BuildNumber",
+			content:  "<Version>1.0.0</Version><BuildNumber>0</BuildNumber>",
+			expected: "<Version>1.0.0</Version><BuildNumber>42</BuildNumber>",
+		},
+		{
+			name:     "regex with two matches",
+			format:   stamp.FormatRegex,
+			key:      `BUILD = (\d+)`,
+			content:  "BUILD = 1\nOTHER = 1\nBUILD = 2\n",
+			expected: "BUILD = 42\nOTHER = 1\nBUILD = 42\n",
+		},
+		{
+			name:    "no match",
+			format:  stamp.FormatGradle,
+			content: "versionName=1.0\n",
+			err:     stamp.ErrNoMatch,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			key := test.key
+			if key == "" {
+				key = test.format.DefaultKey()
+			}
+			replace, err := test.format.Replacer(key)
+			assert.NoError(t, err)
+
+			out, err := replace([]byte(test.content), 42)
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, string(out))
+		})
+	}
+}
+
+func TestPattern(t *testing.T) {
+	t.Run("regex without pattern", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := stamp.FormatRegex.Pattern("")
+		assert.ErrorIs(t, err, stamp.ErrMissingPattern)
+	})
+	t.Run("regex without group", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := stamp.FormatRegex.Pattern(`\d+`)
+		assert.ErrorIs(t, err, stamp.ErrMissingGroup)
+	})
+	t.Run("go", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := stamp.FormatGo.Pattern("main.Version")
+		assert.ErrorIs(t, err, stamp.ErrUnknownFormat)
+	})
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := stamp.ParseFormat("npm")
+	assert.NoError(t, err)
+	assert.Equal(t, stamp.FormatNpm, format)
+
+	_, err = stamp.ParseFormat("invalid")
+	assert.ErrorIs(t, err, stamp.ErrUnknownFormat)
+}
+
+func TestLdFlags(t *testing.T) {
+	assert.Equal(t, "-X main.Version=42", stamp.LdFlags("main.Version", 42))
+}
+
+func TestFiles(t *testing.T) {
+	t.Run("stamps all files", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "gradle.properties")
+		err := os.WriteFile(path, []byte("versionCode=1\n"), 0o600)
+		assert.NoError(t, err)
+
+		replace, _ := stamp.FormatGradle.Replacer("versionCode")
+		err = stamp.Files([]string{path}, replace, 42)
+		assert.NoError(t, err)
+
+		content, _ := os.ReadFile(path)
+		assert.Equal(t, "versionCode=42\n", string(content))
+
+		info, _ := os.Stat(path)
+		assert.Equal(t, os.FileMode(0o600), info.Mode())
+
+		entries, _ := os.ReadDir(filepath.Dir(path))
+		assert.Len(t, entries, 1)
+	})
+	t.Run("leaves all files untouched on failure", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		first := filepath.Join(dir, "first.properties")
+		second := filepath.Join(dir, "second.properties")
+		_ = os.WriteFile(first, []byte("versionCode=1\n"), 0o600)
+		_ = os.WriteFile(second, []byte("versionName=1.0\n"), 0o600)
+
+		replace, _ := stamp.FormatGradle.Replacer("versionCode")
+		err := stamp.Files([]string{first, second}, replace, 42)
+		assert.ErrorIs(t, err, stamp.ErrNoMatch)
+
+		content, _ := os.ReadFile(first)
+		assert.Equal(t, "versionCode=1\n", string(content))
+
+		entries, _ := os.ReadDir(dir)
+		assert.Len(t, entries, 2)
+	})
+}